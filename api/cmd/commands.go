@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/cmd/server"
+	"github.com/venntry/config"
+	"github.com/venntry/internal/features/app/reconcile"
+)
+
+// runCommand runs a maintenance subcommand instead of the server
+func runCommand(name string, args []string, db *sqlx.DB, cfg *config.Variables) error {
+	switch name {
+	case "reconcile-storage":
+		return reconcileStorage(args, db, cfg)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// reconcileStorage compares the bucket with product_images once and prints the report as JSON
+func reconcileStorage(args []string, db *sqlx.DB, cfg *config.Variables) error {
+	flags := flag.NewFlagSet("reconcile-storage", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report orphaned objects without deleting them")
+	grace := flags.Duration("grace", cfg.StorageOrphanGrace, "minimum age of an orphaned object before it is deleted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	objects := server.NewStorage(cfg)
+	if objects == nil {
+		return fmt.Errorf("storage is not configured")
+	}
+
+	reconciler := reconcile.NewReconciler(reconcile.NewReconcileRepository(db), objects, *grace)
+	report, err := reconciler.Run(context.Background(), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
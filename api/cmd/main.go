@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/venntry/cmd/server"
 	"github.com/venntry/config"
 	"github.com/venntry/database"
@@ -24,6 +26,14 @@ func main() {
 	db := database.Connect(*cfg)
 	defer db.Close()
 
+	// Maintenance subcommands, e.g. "reconcile-storage -dry-run", run once and exit
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:], db, cfg); err != nil {
+			logger.Fatal("Command failed", logger.Field("command", os.Args[1]), logger.Field("error", err.Error()))
+		}
+		return
+	}
+
 	// Initialize Redis connection
 	redisCache := cache.NewRedisCache(cfg.RedisUrl)
 	defer redisCache.Close()
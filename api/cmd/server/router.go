@@ -1,15 +1,34 @@
 package server
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/venntry/config"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/features/account/admin"
+	"github.com/venntry/internal/features/account/apikeys"
 	"github.com/venntry/internal/features/account/auth"
 	"github.com/venntry/internal/features/account/inventories"
+	"github.com/venntry/internal/features/account/members"
+	"github.com/venntry/internal/features/account/profile"
+	"github.com/venntry/internal/features/app/exports"
+	"github.com/venntry/internal/features/app/movements"
 	"github.com/venntry/internal/features/app/products"
+	"github.com/venntry/internal/features/app/reconcile"
+	"github.com/venntry/internal/features/app/transfers"
 	"github.com/venntry/internal/features/app/warehouses"
 	"github.com/venntry/internal/routes"
 	"github.com/venntry/pkg/cache"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/mailer"
+	"github.com/venntry/pkg/storage"
+	"golang.org/x/crypto/hkdf"
 )
 
 func ConfigureRoutes(e *echo.Echo, db *sqlx.DB, cache cache.IRedisService, cfg *config.Variables) {
@@ -18,25 +37,164 @@ func ConfigureRoutes(e *echo.Echo, db *sqlx.DB, cache cache.IRedisService, cfg *
 		return ctx.JSON(200, map[string]string{"status": "OK!"})
 	})
 
+	// Shared request budget, applied per route group
+	rateLimiter := newRateLimiter(cfg)
+
+	// Outgoing mail: SMTP in deployed environments, a log/file sink otherwise
+	var mail mailer.IMailer = mailer.NewLogMailer(cfg.MailDir, cfg.MailFrom)
+	if cfg.MailDriver == "smtp" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+
 	// Initialize auth routing components
-	authService := auth.NewService(auth.NewRepository(db), cfg)
+	authService := auth.NewService(auth.NewRepository(db), cache, mail, cfg)
 	authController := auth.NewController(authService)
-	routes.AuthRoutes(e, authController, authService)
+	routes.AuthRoutes(e, authController, authService, rateLimiter)
+
+	// System administration; changes made while impersonating a user are audited
+	adminService := admin.NewService(admin.NewRepository(db), authService)
+	adminController := admin.NewController(adminService)
+	e.Use(admin.AuditImpersonation(adminService))
+	routes.AdminRoutes(e, adminController, authService, rateLimiter)
+
+	// Object storage for uploads; nil when it is not available. The local driver serves its
+	// files and receives signed uploads itself.
+	objects := NewStorage(cfg)
+	if local, ok := objects.(*storage.LocalStorage); ok {
+		e.GET("/files/*", local.Handler())
+		e.PUT("/files/*", local.UploadHandler())
+	}
+
+	// Account self-service routes
+	profileService := profile.NewService(profile.NewRepository(db), auth.NewRepository(db), authService, objects)
+	profileController := profile.NewController(profileService)
+	routes.ProfileRoutes(e, profileController, authService, rateLimiter)
+
+	// Inventory access control shared by all inventory-scoped routes
+	accessService := access.NewService(access.NewRepository(db))
+
+	// API keys for machine clients
+	apiKeyService := apikeys.NewService(apikeys.NewRepository(db), accessService)
+	apiKeyController := apikeys.NewController(apiKeyService)
+	routes.APIKeyRoutes(e, apiKeyController, authService, rateLimiter)
 
 	// Inventory routes
 	inventoriesRepo := inventories.NewRepository(db)
 	inventoriesController := inventories.NewController(inventoriesRepo)
-	routes.InventoryRoutes(e, inventoriesController, authService)
+	routes.InventoryRoutes(e, inventoriesController, authService, rateLimiter, accessService)
+
+	// Inventory member routes
+	membersService := members.NewService(members.NewRepository(db), mail, cfg)
+	membersController := members.NewController(membersService)
+	routes.MemberRoutes(e, membersController, authService, rateLimiter, accessService)
+
+	// Stock movement routes
+	movementRepo := movements.NewMovementRepository(db)
+	movementController := movements.NewMovementController(movementRepo)
+	routes.MovementRoutes(e, movementController, authService, rateLimiter, accessService)
+
+	// Product routes
+	productValidator := products.NewProductValidator(db)
+	productRepo := products.NewProductRepository(db, cache, movementRepo)
+	var imageProcessor *products.ImageProcessor
+	if objects != nil {
+		imageProcessor = products.NewImageProcessor(productRepo, objects)
+		imageProcessor.Start(context.Background())
+		products.NewUploadCollector(productRepo, objects).Start(context.Background())
+	}
+	productController := products.NewProductController(productRepo, productValidator, objects, imageProcessor)
+	routes.ProductRoutes(e, productController, authService, rateLimiter, accessService)
 
 	// Warehouse routes
 	warehouseValidator := warehouses.NewWarehouseValidator(db)
 	warehouseRepo := warehouses.NewWarehouseRepository(db, cache)
-	warehouseController := warehouses.NewWarehouseController(warehouseRepo, warehouseValidator)
-	routes.WarehouseRoutes(e, warehouseController, authService)
+	warehouseController := warehouses.NewWarehouseController(warehouseRepo, warehouseValidator, productRepo)
+	routes.WarehouseRoutes(e, warehouseController, authService, rateLimiter, accessService)
 
-	// Product routes
-	productValidator := products.NewProductValidator(db)
-	productRepo := products.NewProductRepository(db, cache)
-	productController := products.NewProductController(productRepo, productValidator)
-	routes.ProductRoutes(e, productController, authService)
+	// Transfer routes
+	transferRepo := transfers.NewTransferRepository(db, movementRepo)
+	transferController := transfers.NewTransferController(transferRepo, warehouseRepo, productRepo)
+	routes.TransferRoutes(e, transferController, authService, rateLimiter, accessService)
+
+	// Periodic cleanup of bucket objects left behind by deleted products and inventories
+	if objects != nil && cfg.StorageReconcileInterval > 0 {
+		reconciler := reconcile.NewReconciler(reconcile.NewReconcileRepository(db), objects, cfg.StorageOrphanGrace)
+		reconciler.Start(context.Background(), cfg.StorageReconcileInterval)
+	}
+
+	// Export routes, with a background runner for large exports
+	exportRepo := exports.NewExportRepository(db)
+	exportService := exports.NewExportService(exportRepo)
+	exportJobs := exports.NewJobRunner(exportRepo, exportService, cfg.ExportDir)
+	exportJobs.Start(context.Background())
+	exportController := exports.NewExportController(exportRepo, exportService, exportJobs)
+	routes.ExportRoutes(e, exportController, authService, rateLimiter, accessService)
+}
+
+// NewStorage connects to the configured object store, returning nil when it cannot be set up
+func NewStorage(cfg *config.Variables) storage.IStorage {
+	var objects storage.IStorage
+	var err error
+
+	switch cfg.StorageDriver {
+	case storage.DriverR2:
+		objects, err = storage.NewR2Storage(storage.R2Config{
+			AccountID:       cfg.R2AccountID,
+			AccessKeyID:     cfg.R2AccessKeyID,
+			AccessKeySecret: cfg.R2AccessKeySecret,
+			BucketName:      cfg.R2BucketName,
+			PublicURL:       cfg.R2PublicURL,
+		})
+	case storage.DriverS3:
+		objects, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PublicURL:       cfg.StoragePublicURL,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		})
+	case storage.DriverLocal:
+		var signingKey []byte
+		if signingKey, err = localSigningKey(cfg); err == nil {
+			objects, err = storage.NewLocalStorage(cfg.StorageLocalDir, cfg.StoragePublicURL, signingKey)
+		}
+	default:
+		logger.Info("Unknown storage driver; file uploads are disabled", logger.Field("driver", cfg.StorageDriver))
+		return nil
+	}
+
+	if err != nil {
+		logger.LogError("Error configuring storage; file uploads are disabled", err, logger.Field("driver", cfg.StorageDriver))
+		return nil
+	}
+
+	return objects
+}
+
+// localSigningKey derives the key the local driver signs upload URLs with. The derivation keeps
+// it apart from the JWT key even when both come from JWT_SECRET, so neither signature can stand
+// in for the other.
+func localSigningKey(cfg *config.Variables) ([]byte, error) {
+	secret := cfg.StorageSigningSecret
+	if secret == "" {
+		secret = cfg.JWTSecret
+	}
+	if secret == "" {
+		return nil, errors.New("STORAGE_SIGNING_SECRET or JWT_SECRET must be set for local storage")
+	}
+
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte("venntry local storage signing")), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// newRateLimiter builds the Redis rate limiter store; it lives outside ConfigureRoutes, whose
+// cache parameter shadows the package
+func newRateLimiter(cfg *config.Variables) middleware.RateLimiterStore {
+	return cache.NewRateLimiterStore(cfg.RedisUrl, cfg.RateLimitPerMinute)
 }
@@ -32,18 +32,8 @@ func New(db *sqlx.DB, cache cache.IRedisService, cfg *config.Variables) *echo.Ec
 		MaxAge:           86400,
 	}))
 
-	// Rate Limiting using Redis
-	// e.Use(middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
-	// 	Store: cache.NewRateLimiterStore(cfg.RedisUrl),
-	// 	IdentifierExtractor: func(ctx echo.Context) (string, error) {
-	// 		id := ctx.RealIP()
-	// 		return id, nil
-	// 	},
-	// 	DenyHandler: func(context echo.Context, identifier string, err error) error {
-	// 		return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
-	// 	},
-	// }))
-
+	// Rate limiting is applied per route group in ConfigureRoutes, so authenticated
+	// requests can be counted per user
 	ConfigureRoutes(e, db, cache, cfg)
 
 	return e
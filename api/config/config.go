@@ -3,6 +3,10 @@ package config
 import (
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +19,55 @@ type Variables struct {
 	Domain             string
 	CSRFCookieDomain   string
 	RateLimitPerMinute int
+	ExportDir          string
+
+	// Outgoing mail: MailDriver "smtp" delivers through SMTP, anything else logs messages
+	// and writes them to MailDir
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// Object storage for uploaded files. StorageDriver is "r2", "s3" or "local"; it defaults to
+	// r2 when an R2 bucket is configured and to local otherwise. StoragePublicURL is the base
+	// URL objects are served from by the s3 and local drivers. StorageSigningSecret keys the
+	// upload URLs the local driver signs; it falls back to JWTSecret when unset.
+	StorageDriver        string
+	StorageLocalDir      string
+	StoragePublicURL     string
+	StorageSigningSecret string
+
+	// Cloudflare R2 bucket, for the r2 driver
+	R2AccountID       string
+	R2AccessKeyID     string
+	R2AccessKeySecret string
+	R2BucketName      string
+	R2PublicURL       string
+
+	// S3-compatible bucket, for the s3 driver; set S3UsePathStyle for MinIO
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UsePathStyle    bool
+
+	// Bucket reconciliation against product_images. The server runs it every
+	// StorageReconcileInterval (0 disables the schedule) and deletes unreferenced objects once
+	// they are older than StorageOrphanGrace.
+	StorageReconcileInterval time.Duration
+	StorageOrphanGrace       time.Duration
+
+	// OpenID Connect single sign-on; disabled when OIDCIssuerURL is unset. OIDCRedirectURL is
+	// the frontend page the provider returns to.
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+	OIDCScopes       []string
 }
 
 func LoadEnv() *Variables {
@@ -28,13 +81,84 @@ func LoadEnv() *Variables {
 	}
 
 	config := &Variables{
-		DatabaseUrl:        os.Getenv("DATABASE_URL"),
-		RedisUrl:           os.Getenv("REDIS_URL"),
-		JWTSecret:          os.Getenv("JWT_SECRET"),
-		Environment:        env,
-		Domain:             os.Getenv("DOMAIN"),
-		CSRFCookieDomain:   os.Getenv("CSRF_COOKIE_DOMAIN"),
-		RateLimitPerMinute: 60,
+		DatabaseUrl:          os.Getenv("DATABASE_URL"),
+		RedisUrl:             os.Getenv("REDIS_URL"),
+		JWTSecret:            os.Getenv("JWT_SECRET"),
+		Environment:          env,
+		Domain:               os.Getenv("DOMAIN"),
+		CSRFCookieDomain:     os.Getenv("CSRF_COOKIE_DOMAIN"),
+		RateLimitPerMinute:   60,
+		ExportDir:            os.Getenv("EXPORT_DIR"),
+		MailDriver:           os.Getenv("MAIL_DRIVER"),
+		MailFrom:             os.Getenv("MAIL_FROM"),
+		MailDir:              os.Getenv("MAIL_DIR"),
+		SMTPHost:             os.Getenv("SMTP_HOST"),
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		StorageDriver:        os.Getenv("STORAGE_DRIVER"),
+		StorageLocalDir:      os.Getenv("STORAGE_LOCAL_DIR"),
+		StoragePublicURL:     os.Getenv("STORAGE_PUBLIC_URL"),
+		StorageSigningSecret: os.Getenv("STORAGE_SIGNING_SECRET"),
+		R2AccountID:          os.Getenv("R2_ACCOUNT_ID"),
+		R2AccessKeyID:        os.Getenv("R2_ACCESS_KEY_ID"),
+		R2AccessKeySecret:    os.Getenv("R2_ACCESS_KEY_SECRET"),
+		R2BucketName:         os.Getenv("R2_BUCKET_NAME"),
+		R2PublicURL:          os.Getenv("R2_PUBLIC_URL"),
+		S3Endpoint:           os.Getenv("S3_ENDPOINT"),
+		S3Region:             os.Getenv("S3_REGION"),
+		S3Bucket:             os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:        os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey:    os.Getenv("S3_SECRET_ACCESS_KEY"),
+		OIDCIssuerURL:        os.Getenv("OIDC_ISSUER_URL"),
+		OIDCClientID:         os.Getenv("OIDC_CLIENT_ID"),
+		OIDCClientSecret:     os.Getenv("OIDC_CLIENT_SECRET"),
+		OIDCRedirectURL:      os.Getenv("OIDC_REDIRECT_URL"),
+		OIDCScopes:           strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+
+	if perMinute, err := strconv.Atoi(os.Getenv("RATE_LIMIT_PER_MINUTE")); err == nil && perMinute > 0 {
+		config.RateLimitPerMinute = perMinute
+	}
+
+	config.SMTPPort, _ = strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if config.SMTPPort == 0 {
+		config.SMTPPort = 587
+	}
+
+	if len(config.OIDCScopes) == 0 {
+		config.OIDCScopes = []string{"openid", "email", "profile"}
+	}
+
+	if config.MailFrom == "" {
+		config.MailFrom = "Venntry <no-reply@venntry.app>"
+	}
+
+	config.S3UsePathStyle, _ = strconv.ParseBool(os.Getenv("S3_USE_PATH_STYLE"))
+
+	config.StorageReconcileInterval = 24 * time.Hour
+	if interval, err := time.ParseDuration(os.Getenv("STORAGE_RECONCILE_INTERVAL")); err == nil && interval >= 0 {
+		config.StorageReconcileInterval = interval
+	}
+	config.StorageOrphanGrace = 24 * time.Hour
+	if grace, err := time.ParseDuration(os.Getenv("STORAGE_ORPHAN_GRACE")); err == nil && grace > 0 {
+		config.StorageOrphanGrace = grace
+	}
+
+	if config.StorageDriver == "" {
+		config.StorageDriver = "local"
+		if config.R2BucketName != "" {
+			config.StorageDriver = "r2"
+		}
+	}
+	if config.StorageLocalDir == "" {
+		config.StorageLocalDir = filepath.Join(os.TempDir(), "venntry-storage")
+	}
+	if config.StoragePublicURL == "" && config.StorageDriver == "local" {
+		config.StoragePublicURL = "http://localhost:5000/files"
+	}
+
+	if config.ExportDir == "" {
+		config.ExportDir = filepath.Join(os.TempDir(), "venntry-exports")
 	}
 
 	return config
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS inventory_members (
+    inventory_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'editor', 'viewer')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (inventory_id, user_id),
+    CONSTRAINT fk_inventory_members_inventory FOREIGN KEY (inventory_id) REFERENCES inventories (id) ON DELETE CASCADE,
+    CONSTRAINT fk_inventory_members_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS inventory_invitations (
+    id UUID PRIMARY KEY,
+    inventory_id UUID NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'editor', 'viewer')),
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    invited_by UUID NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_inventory_invitations_inventory FOREIGN KEY (inventory_id) REFERENCES inventories (id) ON DELETE CASCADE,
+    CONSTRAINT fk_inventory_invitations_user FOREIGN KEY (invited_by) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Existing inventory owners become owner members
+INSERT INTO inventory_members (inventory_id, user_id, role, created_at, updated_at)
+SELECT id, user_id, 'owner', created_at, updated_at FROM inventories
+ON CONFLICT DO NOTHING;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_inventory_members_user_id ON inventory_members (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_invitations_pending
+    ON inventory_invitations (inventory_id, email) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_inventory_invitations_pending;
+DROP INDEX IF EXISTS idx_inventory_members_user_id;
+
+DROP TABLE IF EXISTS inventory_invitations;
+DROP TABLE IF EXISTS inventory_members;
+-- +goose StatementEnd
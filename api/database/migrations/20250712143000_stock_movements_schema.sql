@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL,
+    warehouse_id UUID,
+    inventory_id UUID NOT NULL,
+    delta INTEGER NOT NULL,
+    balance INTEGER NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('receipt', 'sale', 'adjustment', 'transfer', 'damage', 'count_correction')),
+    reference VARCHAR(100),
+    user_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_stock_movements_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_inventory FOREIGN KEY (inventory_id) REFERENCES inventories (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_movements_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- The ledger is append-only; the only permitted update is clearing user_id when that user is deleted
+CREATE OR REPLACE FUNCTION prevent_stock_movement_update() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.user_id IS NULL AND OLD.user_id IS NOT NULL
+        AND (NEW.id, NEW.product_id, NEW.delta, NEW.balance, NEW.reason, NEW.created_at)
+            = (OLD.id, OLD.product_id, OLD.delta, OLD.balance, OLD.reason, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_stock_movements_append_only
+    BEFORE UPDATE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION prevent_stock_movement_update();
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product ON stock_movements (product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_warehouse ON stock_movements (warehouse_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_inventory ON stock_movements (inventory_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_stock_movements_append_only ON stock_movements;
+DROP FUNCTION IF EXISTS prevent_stock_movement_update();
+
+DROP INDEX IF EXISTS idx_stock_movements_inventory;
+DROP INDEX IF EXISTS idx_stock_movements_warehouse;
+DROP INDEX IF EXISTS idx_stock_movements_product;
+
+DROP TABLE IF EXISTS stock_movements;
+-- +goose StatementEnd
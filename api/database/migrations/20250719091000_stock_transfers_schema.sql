@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id UUID PRIMARY KEY,
+    inventory_id UUID NOT NULL,
+    source_warehouse_id UUID NOT NULL,
+    destination_warehouse_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('in_transit', 'completed', 'cancelled')),
+    reference VARCHAR(100),
+    created_by UUID,
+    received_by UUID,
+    shipped_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    received_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_warehouse_id <> destination_warehouse_id),
+    CONSTRAINT fk_stock_transfers_inventory FOREIGN KEY (inventory_id) REFERENCES inventories (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfers_source FOREIGN KEY (source_warehouse_id) REFERENCES warehouses (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfers_destination FOREIGN KEY (destination_warehouse_id) REFERENCES warehouses (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfers_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL,
+    CONSTRAINT fk_stock_transfers_received_by FOREIGN KEY (received_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS stock_transfer_items (
+    transfer_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (transfer_id, product_id),
+    CONSTRAINT fk_stock_transfer_items_transfer FOREIGN KEY (transfer_id) REFERENCES stock_transfers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_transfer_items_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_inventory ON stock_transfers (inventory_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_source ON stock_transfers (source_warehouse_id);
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_destination ON stock_transfers (destination_warehouse_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_stock_transfers_destination;
+DROP INDEX IF EXISTS idx_stock_transfers_source;
+DROP INDEX IF EXISTS idx_stock_transfers_inventory;
+
+DROP TABLE IF EXISTS stock_transfer_items;
+DROP TABLE IF EXISTS stock_transfers;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Warehouse-stocked products derive their quantity from warehouse_product_map. Stock that was
+-- only recorded on the product moves into its oldest warehouse link through the ledger.
+WITH totals AS (
+    SELECT product_id, SUM(quantity) AS total
+    FROM warehouse_product_map
+    GROUP BY product_id
+),
+gaps AS (
+    SELECT DISTINCT ON (p.id) p.id AS product_id, wp.warehouse_id, p.inventory_id, p.quantity - t.total AS delta
+    FROM products p
+    INNER JOIN totals t ON t.product_id = p.id
+    INNER JOIN warehouse_product_map wp ON wp.product_id = p.id
+    WHERE p.quantity > t.total
+    ORDER BY p.id, wp.created_at, wp.warehouse_id
+),
+moved AS (
+    UPDATE warehouse_product_map wp
+    SET quantity = wp.quantity + g.delta
+    FROM gaps g
+    WHERE wp.product_id = g.product_id AND wp.warehouse_id = g.warehouse_id
+    RETURNING wp.product_id, wp.warehouse_id, wp.quantity, g.delta, g.inventory_id
+)
+INSERT INTO stock_movements (id, product_id, warehouse_id, inventory_id, delta, balance, reason, reference, created_at)
+SELECT gen_random_uuid(), product_id, warehouse_id, inventory_id, delta, quantity, 'transfer', 'Unassigned stock', CURRENT_TIMESTAMP
+FROM moved;
+
+UPDATE products p
+SET quantity = t.total
+FROM (
+    SELECT product_id, SUM(quantity) AS total
+    FROM warehouse_product_map
+    GROUP BY product_id
+) t
+WHERE p.id = t.product_id AND p.quantity <> t.total;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Quantities moved into warehouses are kept; the ledger records how they got there.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keyset pagination indexes for the sortable product list columns
+CREATE INDEX IF NOT EXISTS idx_products_inventory_created ON products (inventory_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_products_inventory_updated ON products (inventory_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_products_inventory_name ON products (inventory_id, name, id);
+CREATE INDEX IF NOT EXISTS idx_products_inventory_price ON products (inventory_id, price, id);
+CREATE INDEX IF NOT EXISTS idx_products_inventory_cost ON products (inventory_id, cost, id);
+CREATE INDEX IF NOT EXISTS idx_products_inventory_quantity ON products (inventory_id, quantity, id);
+CREATE INDEX IF NOT EXISTS idx_products_inventory_brand ON products (inventory_id, LOWER(brand));
+CREATE INDEX IF NOT EXISTS idx_product_category_map_category ON product_category_map (category_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_product_category_map_category;
+DROP INDEX IF EXISTS idx_products_inventory_brand;
+DROP INDEX IF EXISTS idx_products_inventory_quantity;
+DROP INDEX IF EXISTS idx_products_inventory_cost;
+DROP INDEX IF EXISTS idx_products_inventory_price;
+DROP INDEX IF EXISTS idx_products_inventory_name;
+DROP INDEX IF EXISTS idx_products_inventory_updated;
+DROP INDEX IF EXISTS idx_products_inventory_created;
+-- +goose StatementEnd
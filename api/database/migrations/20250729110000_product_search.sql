@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Must match productSearchDocument in the products repository for the planner to use it
+CREATE INDEX IF NOT EXISTS idx_products_search_document ON products USING GIN (
+    to_tsvector('simple',
+        COALESCE(name, '') || ' ' || COALESCE(sku, '') || ' ' || COALESCE(code, '') || ' ' ||
+        COALESCE(brand, '') || ' ' || COALESCE(model, '') || ' ' || COALESCE(description, ''))
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_sku_trgm ON products USING GIN (sku gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_code_trgm ON products USING GIN (code gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_brand_trgm ON products USING GIN (brand gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_model_trgm ON products USING GIN (model gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_product_categories_name_trgm ON product_categories USING GIN (name gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_products_inventory_sku_lower ON products (inventory_id, LOWER(sku));
+CREATE INDEX IF NOT EXISTS idx_products_inventory_code_lower ON products (inventory_id, LOWER(code));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_inventory_code_lower;
+DROP INDEX IF EXISTS idx_products_inventory_sku_lower;
+DROP INDEX IF EXISTS idx_product_categories_name_trgm;
+DROP INDEX IF EXISTS idx_products_model_trgm;
+DROP INDEX IF EXISTS idx_products_brand_trgm;
+DROP INDEX IF EXISTS idx_products_code_trgm;
+DROP INDEX IF EXISTS idx_products_sku_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_search_document;
+-- +goose StatementEnd
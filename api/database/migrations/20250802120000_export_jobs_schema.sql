@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id UUID PRIMARY KEY,
+    inventory_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('products', 'warehouses', 'movements')),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx', 'json')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    query JSONB NOT NULL,
+    file_path TEXT,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT fk_export_jobs_inventory FOREIGN KEY (inventory_id) REFERENCES inventories (id) ON DELETE CASCADE,
+    CONSTRAINT fk_export_jobs_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_inventory ON export_jobs (inventory_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_status ON export_jobs (status);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_export_jobs_expires;
+DROP INDEX IF EXISTS idx_export_jobs_status;
+DROP INDEX IF EXISTS idx_export_jobs_inventory;
+DROP TABLE IF EXISTS export_jobs;
+-- +goose StatementEnd
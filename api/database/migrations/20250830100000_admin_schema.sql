@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_reason VARCHAR(255);
+
+-- Sessions an administrator opened as another user
+ALTER TABLE auth_sessions ADD COLUMN IF NOT EXISTS impersonator_id UUID REFERENCES users (id) ON DELETE CASCADE;
+
+-- Object size, so storage use can be reported per inventory
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS size_bytes BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id UUID PRIMARY KEY,
+    admin_id UUID,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id UUID,
+    details JSONB NOT NULL DEFAULT '{}',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_admin_audit_log_admin FOREIGN KEY (admin_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_admin_id ON admin_audit_log (admin_id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target_id ON admin_audit_log (target_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_admin_audit_log_target_id;
+DROP INDEX IF EXISTS idx_admin_audit_log_admin_id;
+DROP INDEX IF EXISTS idx_admin_audit_log_created_at;
+DROP TABLE IF EXISTS admin_audit_log;
+ALTER TABLE product_images DROP COLUMN IF EXISTS size_bytes;
+ALTER TABLE auth_sessions DROP COLUMN IF EXISTS impersonator_id;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
+-- +goose StatementEnd
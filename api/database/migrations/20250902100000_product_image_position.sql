@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+
+-- Keep the order images were shown in before: primary first, then oldest first
+UPDATE product_images pi SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY is_primary DESC, created_at ASC) - 1 AS position
+    FROM product_images
+) ordered
+WHERE pi.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_position ON product_images (product_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_product_images_product_position;
+ALTER TABLE product_images DROP COLUMN IF EXISTS position;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing images start out pending so the processor backfills their variants
+ALTER TABLE product_images
+    ADD COLUMN IF NOT EXISTS processing_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (processing_status IN ('pending', 'processing', 'ready', 'failed')),
+    ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_product_images_pending ON product_images (created_at)
+    WHERE processing_status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_product_images_pending;
+ALTER TABLE product_images
+    DROP COLUMN IF EXISTS variants,
+    DROP COLUMN IF EXISTS processing_status;
+-- +goose StatementEnd
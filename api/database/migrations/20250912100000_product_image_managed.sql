@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Marks images whose file the server stored itself. Keys of older images came from clients and
+-- may point at anyone's object, so only managed keys are ever rewritten or deleted.
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS managed BOOLEAN NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_managed_key ON product_images (file_key)
+    WHERE managed;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_product_images_managed_key;
+ALTER TABLE product_images DROP COLUMN IF EXISTS managed;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Images with client-supplied keys are never processed; settle the ones still waiting
+UPDATE product_images SET processing_status = 'failed'
+WHERE NOT managed AND processing_status IN ('pending', 'processing');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE product_images SET processing_status = 'pending'
+WHERE NOT managed AND processing_status = 'failed' AND variants = '[]';
+-- +goose StatementEnd
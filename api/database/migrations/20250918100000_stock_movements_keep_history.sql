@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleting a product or warehouse no longer erases its ledger entries; they stay in the
+-- inventory's history with the reference cleared.
+ALTER TABLE stock_movements ALTER COLUMN product_id DROP NOT NULL;
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS fk_stock_movements_product;
+ALTER TABLE stock_movements ADD CONSTRAINT fk_stock_movements_product
+    FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE SET NULL;
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS fk_stock_movements_warehouse;
+ALTER TABLE stock_movements ADD CONSTRAINT fk_stock_movements_warehouse
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON DELETE SET NULL;
+
+-- The ledger is append-only; the only permitted update is clearing the product, warehouse or
+-- user a movement refers to when that row is deleted. Every other column must stay as it was.
+CREATE OR REPLACE FUNCTION prevent_stock_movement_update() RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.id, NEW.inventory_id, NEW.delta, NEW.balance, NEW.reason, NEW.reference, NEW.created_at)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.inventory_id, OLD.delta, OLD.balance, OLD.reason, OLD.reference, OLD.created_at)
+        AND (NEW.product_id IS NOT DISTINCT FROM OLD.product_id OR NEW.product_id IS NULL)
+        AND (NEW.warehouse_id IS NOT DISTINCT FROM OLD.warehouse_id OR NEW.warehouse_id IS NULL)
+        AND (NEW.user_id IS NOT DISTINCT FROM OLD.user_id OR NEW.user_id IS NULL) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_stock_movement_update() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.user_id IS NULL AND OLD.user_id IS NOT NULL
+        AND (NEW.id, NEW.product_id, NEW.delta, NEW.balance, NEW.reason, NEW.created_at)
+            = (OLD.id, OLD.product_id, OLD.delta, OLD.balance, OLD.reason, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Entries of deleted products cannot satisfy the restored constraint
+DELETE FROM stock_movements WHERE product_id IS NULL;
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS fk_stock_movements_warehouse;
+ALTER TABLE stock_movements ADD CONSTRAINT fk_stock_movements_warehouse
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON DELETE CASCADE;
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS fk_stock_movements_product;
+ALTER TABLE stock_movements ADD CONSTRAINT fk_stock_movements_product
+    FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
+
+ALTER TABLE stock_movements ALTER COLUMN product_id SET NOT NULL;
+-- +goose StatementEnd
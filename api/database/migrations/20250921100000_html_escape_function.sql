@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Escapes text for HTML. Search highlights run ts_headline over escaped text, so the only
+-- markup in them is the <mark> tags it adds; the parser treats the entities as single tokens
+-- and never highlights inside them.
+CREATE OR REPLACE FUNCTION html_escape(input TEXT) RETURNS TEXT AS $$
+    SELECT replace(replace(replace(replace(replace(input,
+        '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;');
+$$ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS html_escape(TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+package access
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type IAccessRepository interface {
+	InventoryExists(inventoryId uuid.UUID) (bool, error)
+	GetInventoryMember(inventoryId, userId uuid.UUID) (*models.InventoryMember, error)
+	MeetsTwoFactorPolicy(inventoryId, userId uuid.UUID) (bool, error)
+	GetProductInventoryId(productId uuid.UUID) (uuid.UUID, error)
+	GetWarehouseInventoryId(warehouseId uuid.UUID) (uuid.UUID, error)
+}
+
+type IAccessService interface {
+	AuthorizeInventory(userId, inventoryId uuid.UUID) (*models.InventoryMember, error)
+	AuthorizeProduct(userId, productId uuid.UUID) (*models.InventoryMember, error)
+	AuthorizeWarehouse(userId, warehouseId uuid.UUID) (*models.InventoryMember, error)
+}
+
+// ResourceResolver maps the resource addressed by a request to the caller's membership
+// in the inventory that owns it.
+type ResourceResolver func(ctx echo.Context, service IAccessService, userId uuid.UUID) (*models.InventoryMember, error)
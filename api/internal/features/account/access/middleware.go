@@ -0,0 +1,103 @@
+package access
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+// InventoryMiddleware resolves the inventory behind the current route and rejects
+// callers that are not members of it. It must run after auth.AuthMiddleware.
+func InventoryMiddleware(service IAccessService, resolve ResourceResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			user, ok := ctx.Get("user").(*models.User)
+			if !ok {
+				return logger.Error(ctx, "User not found in context",
+					errors.UnauthorizedError("Unauthorized user"))
+			}
+
+			member, err := resolve(ctx, service, user.Id)
+			if err != nil {
+				return logger.Error(ctx, "Inventory access denied", err,
+					logger.Field("user_id", user.Id),
+					logger.Field("path", ctx.Path()),
+				)
+			}
+
+			// Inventory-bound API keys only reach their own inventory
+			if key, ok := ctx.Get("apiKey").(*models.APIKey); ok && key.InventoryID != nil && *key.InventoryID != member.InventoryId {
+				return logger.Error(ctx, "Inventory access denied",
+					errors.ForbiddenError("This API key is not valid for this inventory"),
+					logger.Field("api_key_id", key.ID),
+				)
+			}
+
+			ctx.Set("inventoryId", member.InventoryId)
+			ctx.Set("inventoryRole", member.Role)
+			return next(ctx)
+		}
+	}
+}
+
+// RequireInventoryRole rejects members whose role in the resolved inventory is below
+// minimum. It must run after InventoryMiddleware.
+func RequireInventoryRole(minimum string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			role, ok := ctx.Get("inventoryRole").(string)
+			if !ok {
+				return logger.Error(ctx, "Inventory role not found in context",
+					errors.ForbiddenError("Unauthorized user"))
+			}
+
+			if !HasRole(role, minimum) {
+				return logger.Error(ctx, "Access forbidden: insufficient inventory role",
+					errors.ForbiddenError("Insufficient permissions for this inventory"),
+					logger.Field("role", role),
+					logger.Field("required_role", minimum),
+				)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// FromInventory resolves the inventory directly from a route parameter.
+func FromInventory(param string) ResourceResolver {
+	return func(ctx echo.Context, service IAccessService, userId uuid.UUID) (*models.InventoryMember, error) {
+		inventoryId, err := uuid.Parse(ctx.Param(param))
+		if err != nil {
+			return nil, errors.ValidationError("Invalid inventory ID")
+		}
+
+		return service.AuthorizeInventory(userId, inventoryId)
+	}
+}
+
+// FromProduct resolves the inventory that owns the product in a route parameter.
+func FromProduct(param string) ResourceResolver {
+	return func(ctx echo.Context, service IAccessService, userId uuid.UUID) (*models.InventoryMember, error) {
+		productId, err := uuid.Parse(ctx.Param(param))
+		if err != nil {
+			return nil, errors.ValidationError("Invalid product ID")
+		}
+
+		return service.AuthorizeProduct(userId, productId)
+	}
+}
+
+// FromWarehouse resolves the inventory that owns the warehouse in a route parameter.
+func FromWarehouse(param string) ResourceResolver {
+	return func(ctx echo.Context, service IAccessService, userId uuid.UUID) (*models.InventoryMember, error) {
+		warehouseId, err := uuid.Parse(ctx.Param(param))
+		if err != nil {
+			return nil, errors.ValidationError("Invalid warehouse ID")
+		}
+
+		return service.AuthorizeWarehouse(userId, warehouseId)
+	}
+}
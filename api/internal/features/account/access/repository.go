@@ -0,0 +1,92 @@
+package access
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(data *sqlx.DB) IAccessRepository {
+	return &Repository{db: data}
+}
+
+func (repo *Repository) InventoryExists(inventoryId uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM inventories WHERE id = $1)`
+
+	err := repo.db.Get(&exists, query, inventoryId)
+	if err != nil {
+		return false, errors.DatabaseError(err, "Error checking inventory")
+	}
+
+	return exists, nil
+}
+
+func (repo *Repository) GetInventoryMember(inventoryId, userId uuid.UUID) (*models.InventoryMember, error) {
+	var member models.InventoryMember
+	query := `SELECT inventory_id, user_id, role, created_at, updated_at
+			  FROM inventory_members WHERE inventory_id = $1 AND user_id = $2`
+
+	err := repo.db.Get(&member, query, inventoryId, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.DatabaseError(err, "Error checking inventory membership")
+	}
+
+	return &member, nil
+}
+
+// MeetsTwoFactorPolicy reports whether the user may access the inventory under its two-factor
+// requirement
+func (repo *Repository) MeetsTwoFactorPolicy(inventoryId, userId uuid.UUID) (bool, error) {
+	var allowed bool
+	query := `SELECT NOT i.require_two_factor OR EXISTS(
+				SELECT 1 FROM user_two_factor t WHERE t.user_id = $2 AND t.enabled_at IS NOT NULL)
+			  FROM inventories i WHERE i.id = $1`
+
+	err := repo.db.Get(&allowed, query, inventoryId, userId)
+	if err != nil {
+		return false, errors.DatabaseError(err, "Error checking two-factor policy")
+	}
+
+	return allowed, nil
+}
+
+func (repo *Repository) GetProductInventoryId(productId uuid.UUID) (uuid.UUID, error) {
+	var inventoryId uuid.UUID
+	query := `SELECT inventory_id FROM products WHERE id = $1`
+
+	err := repo.db.Get(&inventoryId, query, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, errors.NotFoundError("Product not found")
+		}
+		return uuid.Nil, errors.DatabaseError(err, "Error resolving product inventory")
+	}
+
+	return inventoryId, nil
+}
+
+func (repo *Repository) GetWarehouseInventoryId(warehouseId uuid.UUID) (uuid.UUID, error) {
+	var inventoryId uuid.UUID
+	query := `SELECT inventory_id FROM warehouses WHERE id = $1`
+
+	err := repo.db.Get(&inventoryId, query, warehouseId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, errors.NotFoundError("Warehouse not found")
+		}
+		return uuid.Nil, errors.DatabaseError(err, "Error resolving warehouse inventory")
+	}
+
+	return inventoryId, nil
+}
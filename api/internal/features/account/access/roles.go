@@ -0,0 +1,24 @@
+package access
+
+import "github.com/venntry/internal/models"
+
+var roleRanks = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+	models.RoleOwner:  4,
+}
+
+// HasRole reports whether role grants at least the privileges of minimum.
+func HasRole(role, minimum string) bool {
+	rank, ok := roleRanks[role]
+	if !ok {
+		return false
+	}
+	return rank >= roleRanks[minimum]
+}
+
+// Outranks reports whether role is strictly more privileged than other.
+func Outranks(role, other string) bool {
+	return roleRanks[role] > roleRanks[other]
+}
@@ -0,0 +1,61 @@
+package access
+
+import (
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type Service struct {
+	repo IAccessRepository
+}
+
+func NewService(repo IAccessRepository) IAccessService {
+	return &Service{repo: repo}
+}
+
+func (service *Service) AuthorizeInventory(userId, inventoryId uuid.UUID) (*models.InventoryMember, error) {
+	exists, err := service.repo.InventoryExists(inventoryId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NotFoundError("Inventory not found")
+	}
+
+	member, err := service.repo.GetInventoryMember(inventoryId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.ForbiddenError("You do not have access to this inventory")
+	}
+
+	allowed, err := service.repo.MeetsTwoFactorPolicy(inventoryId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.ForbiddenError("This inventory requires two-factor authentication")
+	}
+
+	return member, nil
+}
+
+func (service *Service) AuthorizeProduct(userId, productId uuid.UUID) (*models.InventoryMember, error) {
+	inventoryId, err := service.repo.GetProductInventoryId(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AuthorizeInventory(userId, inventoryId)
+}
+
+func (service *Service) AuthorizeWarehouse(userId, warehouseId uuid.UUID) (*models.InventoryMember, error) {
+	inventoryId, err := service.repo.GetWarehouseInventoryId(warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AuthorizeInventory(userId, inventoryId)
+}
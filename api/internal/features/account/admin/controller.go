@@ -0,0 +1,207 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type Controller struct {
+	service IAdminService
+}
+
+func NewController(service IAdminService) *Controller {
+	return &Controller{service: service}
+}
+
+func (ctrl *Controller) ListUsers(ctx echo.Context) error {
+	var filter models.AdminUserFilter
+	if err := utils.BindAndValidateRequest(ctx, &filter); err != nil {
+		return err
+	}
+
+	page, limit := utils.ParsePagination(ctx)
+	response, err := ctrl.service.ListUsers(&filter, page, limit)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch users", err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl *Controller) GetUser(ctx echo.Context) error {
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	user, err := ctrl.service.GetUser(userId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch user", err,
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (ctrl *Controller) SuspendUser(ctx echo.Context) error {
+	admin, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	var req models.SuspendUserRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	user, err := ctrl.service.SuspendUser(admin, auth.ClientInfo(ctx), userId, req.Reason)
+	if err != nil {
+		return logger.Error(ctx, "Failed to suspend user", err,
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (ctrl *Controller) ReactivateUser(ctx echo.Context) error {
+	admin, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	user, err := ctrl.service.ReactivateUser(admin, auth.ClientInfo(ctx), userId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to reactivate user", err,
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, user)
+}
+
+func (ctrl *Controller) ResetPassword(ctx echo.Context) error {
+	admin, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	if err := ctrl.service.ResetPassword(admin, auth.ClientInfo(ctx), userId); err != nil {
+		return logger.Error(ctx, "Failed to send password reset", err,
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.JSON(http.StatusAccepted, map[string]string{"message": "A password reset link has been sent"})
+}
+
+// UnlockUser lets an administrator lift a login lockout
+func (ctrl *Controller) UnlockUser(ctx echo.Context) error {
+	admin, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	if err := ctrl.service.UnlockUser(admin, auth.ClientInfo(ctx), userId); err != nil {
+		return logger.Error(ctx, "Account unlock failed", err,
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *Controller) ListUserInventories(ctx echo.Context) error {
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	inventories, err := ctrl.service.ListUserInventories(userId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch user inventories", err,
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, inventories)
+}
+
+func (ctrl *Controller) Impersonate(ctx echo.Context) error {
+	admin, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	var req models.ImpersonationRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	response, err := ctrl.service.Impersonate(admin, auth.ClientInfo(ctx), userId, req.Reason)
+	if err != nil {
+		return logger.Error(ctx, "Failed to impersonate user", err,
+			logger.Field("admin_id", admin.Id),
+			logger.Field("user_id", userId),
+		)
+	}
+
+	logger.Info("Impersonation session started",
+		logger.Field("admin_id", admin.Id),
+		logger.Field("user_id", userId),
+	)
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (ctrl *Controller) ListAuditLog(ctx echo.Context) error {
+	var filter models.AuditLogFilter
+	if err := utils.BindAndValidateRequest(ctx, &filter); err != nil {
+		return err
+	}
+
+	page, limit := utils.ParsePagination(ctx)
+	response, err := ctrl.service.ListAuditLog(&filter, page, limit)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch audit log", err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type IAdminRepository interface {
+	ListUsers(filter *models.AdminUserFilter, limit, offset int) ([]models.AdminUser, int, error)
+	GetUser(userId uuid.UUID) (*models.AdminUser, error)
+	SuspendUser(userId uuid.UUID, reason string) error
+	ReactivateUser(userId uuid.UUID) error
+	ListUserInventories(userId uuid.UUID) ([]models.AdminInventory, error)
+
+	CreateAuditEntry(entry *models.AuditEntry) error
+	ListAuditEntries(filter *models.AuditLogFilter, limit, offset int) ([]models.AuditEntry, int, error)
+}
+
+type IAdminService interface {
+	ListUsers(filter *models.AdminUserFilter, page, limit int) (*models.AdminUserPage, error)
+	GetUser(userId uuid.UUID) (*models.AdminUser, error)
+	SuspendUser(admin *models.User, client models.ClientInfo, userId uuid.UUID, reason string) (*models.AdminUser, error)
+	ReactivateUser(admin *models.User, client models.ClientInfo, userId uuid.UUID) (*models.AdminUser, error)
+	ResetPassword(admin *models.User, client models.ClientInfo, userId uuid.UUID) error
+	UnlockUser(admin *models.User, client models.ClientInfo, userId uuid.UUID) error
+	ListUserInventories(userId uuid.UUID) ([]models.AdminInventory, error)
+
+	Impersonate(admin *models.User, client models.ClientInfo, userId uuid.UUID, reason string) (*models.ImpersonationResponse, error)
+	RecordImpersonatedRequest(impersonatorId, userId uuid.UUID, client models.ClientInfo, method, path string, status int)
+
+	ListAuditLog(filter *models.AuditLogFilter, page, limit int) (*models.AuditLogPage, error)
+}
+
+type IAdminController interface {
+	ListUsers(ctx echo.Context) error
+	GetUser(ctx echo.Context) error
+	SuspendUser(ctx echo.Context) error
+	ReactivateUser(ctx echo.Context) error
+	ResetPassword(ctx echo.Context) error
+	UnlockUser(ctx echo.Context) error
+	ListUserInventories(ctx echo.Context) error
+	Impersonate(ctx echo.Context) error
+	ListAuditLog(ctx echo.Context) error
+}
@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+// AuditImpersonation records every change made through an impersonation session. It wraps
+// the whole router, so it reads the context that AuthMiddleware filled in once the request
+// has been handled.
+func AuditImpersonation(service IAdminService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			err := next(ctx)
+
+			impersonatorId, ok := ctx.Get("impersonatorId").(uuid.UUID)
+			if !ok || isReadOnlyMethod(ctx.Request().Method) {
+				return err
+			}
+			user, ok := ctx.Get("user").(*models.User)
+			if !ok {
+				return err
+			}
+
+			// Errors are written by the central handler after this returns
+			status := ctx.Response().Status
+			switch e := err.(type) {
+			case *errors.AppError:
+				status = e.Code
+			case *echo.HTTPError:
+				status = e.Code
+			}
+
+			service.RecordImpersonatedRequest(impersonatorId, user.Id, auth.ClientInfo(ctx),
+				ctx.Request().Method, ctx.Request().URL.Path, status)
+			return err
+		}
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,193 @@
+package admin
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(data *sqlx.DB) IAdminRepository {
+	return &Repository{db: data}
+}
+
+// Last seen ignores sessions opened by administrators acting as the user
+const adminUserColumns = `u.id, u.username, u.email, u.role, u.email_verified_at, u.suspended_at, u.suspended_reason, u.created_at,
+			  (SELECT COUNT(*) FROM inventory_members m WHERE m.user_id = u.id) AS inventory_count,
+			  (SELECT MAX(s.last_used_at) FROM auth_sessions s WHERE s.user_id = u.id AND s.impersonator_id IS NULL) AS last_seen_at`
+
+func (repo *Repository) ListUsers(filter *models.AdminUserFilter, limit, offset int) ([]models.AdminUser, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		args = append(args, "%"+strings.ToLower(search)+"%")
+		conditions = append(conditions, "(u.username LIKE $"+strconv.Itoa(len(args))+" OR u.email LIKE $"+strconv.Itoa(len(args))+")")
+	}
+	switch filter.Status {
+	case "active":
+		conditions = append(conditions, "u.suspended_at IS NULL")
+	case "suspended":
+		conditions = append(conditions, "u.suspended_at IS NOT NULL")
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, "u.role = $"+strconv.Itoa(len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := repo.db.Get(&total, `SELECT COUNT(*) FROM users u`+where, args...); err != nil {
+		return nil, 0, errors.DatabaseError(err, "Error counting users")
+	}
+
+	users := []models.AdminUser{}
+	query := `SELECT ` + adminUserColumns + `
+			  FROM users u` + where + `
+			  ORDER BY u.created_at DESC, u.id DESC
+			  LIMIT $` + strconv.Itoa(len(args)+1) + ` OFFSET $` + strconv.Itoa(len(args)+2)
+	if err := repo.db.Select(&users, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, errors.DatabaseError(err, "Error fetching users")
+	}
+
+	return users, total, nil
+}
+
+func (repo *Repository) GetUser(userId uuid.UUID) (*models.AdminUser, error) {
+	var user models.AdminUser
+	query := `SELECT ` + adminUserColumns + `
+			  FROM users u
+			  WHERE u.id = $1`
+
+	err := repo.db.Get(&user, query, userId)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundError("User not found")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving user")
+	}
+
+	return &user, nil
+}
+
+func (repo *Repository) SuspendUser(userId uuid.UUID, reason string) error {
+	now := time.Now()
+	result, err := repo.db.Exec(`
+		UPDATE users SET suspended_at = $2, suspended_reason = $3, updated_at = $2
+		WHERE id = $1`, userId, now, reason)
+	if err != nil {
+		return errors.DatabaseError(err, "Error suspending user")
+	}
+
+	return requireAffected(result, "User not found")
+}
+
+func (repo *Repository) ReactivateUser(userId uuid.UUID) error {
+	result, err := repo.db.Exec(`
+		UPDATE users SET suspended_at = NULL, suspended_reason = NULL, updated_at = $2
+		WHERE id = $1`, userId, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error reactivating user")
+	}
+
+	return requireAffected(result, "User not found")
+}
+
+// ListUserInventories returns every inventory the user belongs to with its usage. Storage is
+// the total size of the inventory's product images and their variants.
+func (repo *Repository) ListUserInventories(userId uuid.UUID) ([]models.AdminInventory, error) {
+	inventories := []models.AdminInventory{}
+	query := `SELECT i.id, i.name, i.user_id, m.role, i.created_at,
+			  (SELECT COUNT(*) FROM products p WHERE p.inventory_id = i.id) AS product_count,
+			  (SELECT COUNT(*) FROM warehouses w WHERE w.inventory_id = i.id) AS warehouse_count,
+			  (SELECT COUNT(*) FROM inventory_members im WHERE im.inventory_id = i.id) AS member_count,
+			  (SELECT COALESCE(SUM(pi.size_bytes + (
+			       SELECT COALESCE(SUM((v->>'sizeBytes')::BIGINT), 0) FROM jsonb_array_elements(pi.variants) v
+			   )), 0) FROM product_images pi
+			   INNER JOIN products p ON p.id = pi.product_id
+			   WHERE p.inventory_id = i.id) AS storage_bytes
+			  FROM inventories i
+			  INNER JOIN inventory_members m ON m.inventory_id = i.id
+			  WHERE m.user_id = $1
+			  ORDER BY i.created_at ASC`
+
+	if err := repo.db.Select(&inventories, query, userId); err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving user inventories")
+	}
+
+	return inventories, nil
+}
+
+func (repo *Repository) CreateAuditEntry(entry *models.AuditEntry) error {
+	_, err := repo.db.NamedExec(`
+		INSERT INTO admin_audit_log (id, admin_id, action, target_type, target_id, details, ip_address, created_at)
+		VALUES (:id, :admin_id, :action, :target_type, :target_id, :details, :ip_address, :created_at)`, entry)
+	if err != nil {
+		return errors.DatabaseError(err, "Error recording audit entry")
+	}
+
+	return nil
+}
+
+func (repo *Repository) ListAuditEntries(filter *models.AuditLogFilter, limit, offset int) ([]models.AuditEntry, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.AdminID != "" {
+		args = append(args, filter.AdminID)
+		conditions = append(conditions, "admin_id = $"+strconv.Itoa(len(args)))
+	}
+	if filter.TargetID != "" {
+		args = append(args, filter.TargetID)
+		conditions = append(conditions, "target_id = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, "action = $"+strconv.Itoa(len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := repo.db.Get(&total, `SELECT COUNT(*) FROM admin_audit_log`+where, args...); err != nil {
+		return nil, 0, errors.DatabaseError(err, "Error counting audit entries")
+	}
+
+	entries := []models.AuditEntry{}
+	query := `SELECT * FROM admin_audit_log` + where + `
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $` + strconv.Itoa(len(args)+1) + ` OFFSET $` + strconv.Itoa(len(args)+2)
+	if err := repo.db.Select(&entries, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, errors.DatabaseError(err, "Error fetching audit entries")
+	}
+
+	return entries, total, nil
+}
+
+// Helper functions
+func requireAffected(result sql.Result, message string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.DatabaseError(err, "Error checking affected rows")
+	}
+	if rows == 0 {
+		return errors.NotFoundError(message)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type Service struct {
+	repo IAdminRepository
+	auth auth.IAuthService
+}
+
+func NewService(repo IAdminRepository, authService auth.IAuthService) IAdminService {
+	return &Service{repo: repo, auth: authService}
+}
+
+func (service *Service) ListUsers(filter *models.AdminUserFilter, page, limit int) (*models.AdminUserPage, error) {
+	users, total, err := service.repo.ListUsers(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AdminUserPage{Users: users, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (service *Service) GetUser(userId uuid.UUID) (*models.AdminUser, error) {
+	return service.repo.GetUser(userId)
+}
+
+// SuspendUser blocks the account and signs it out everywhere. API keys stop working while
+// the account is suspended but are kept for when it is reactivated.
+func (service *Service) SuspendUser(admin *models.User, client models.ClientInfo, userId uuid.UUID, reason string) (*models.AdminUser, error) {
+	if admin.Id == userId {
+		return nil, errors.ValidationError("You cannot suspend your own account")
+	}
+
+	if err := service.repo.SuspendUser(userId, reason); err != nil {
+		return nil, err
+	}
+
+	if err := service.auth.RevokeUserSessions(userId, uuid.Nil, models.RevokedSuspended); err != nil {
+		return nil, err
+	}
+
+	service.record(admin.Id, client, models.AuditUserSuspend, userId, map[string]interface{}{"reason": reason})
+	return service.repo.GetUser(userId)
+}
+
+func (service *Service) ReactivateUser(admin *models.User, client models.ClientInfo, userId uuid.UUID) (*models.AdminUser, error) {
+	if err := service.repo.ReactivateUser(userId); err != nil {
+		return nil, err
+	}
+
+	service.record(admin.Id, client, models.AuditUserReactivate, userId, nil)
+	return service.repo.GetUser(userId)
+}
+
+// ResetPassword emails the user a reset link; administrators never see or set the password
+func (service *Service) ResetPassword(admin *models.User, client models.ClientInfo, userId uuid.UUID) error {
+	if err := service.auth.SendPasswordReset(userId); err != nil {
+		return err
+	}
+
+	service.record(admin.Id, client, models.AuditUserPasswordReset, userId, nil)
+	return nil
+}
+
+func (service *Service) UnlockUser(admin *models.User, client models.ClientInfo, userId uuid.UUID) error {
+	if err := service.auth.UnlockUser(userId); err != nil {
+		return err
+	}
+
+	service.record(admin.Id, client, models.AuditUserUnlock, userId, nil)
+	return nil
+}
+
+func (service *Service) ListUserInventories(userId uuid.UUID) ([]models.AdminInventory, error) {
+	if _, err := service.repo.GetUser(userId); err != nil {
+		return nil, err
+	}
+
+	return service.repo.ListUserInventories(userId)
+}
+
+// Impersonate lets support act as a user. The reason is required and recorded, as is every
+// change made through the session (see AuditImpersonation).
+func (service *Service) Impersonate(admin *models.User, client models.ClientInfo, userId uuid.UUID, reason string) (*models.ImpersonationResponse, error) {
+	response, err := service.auth.Impersonate(admin, userId, client)
+	if err != nil {
+		return nil, err
+	}
+
+	service.record(admin.Id, client, models.AuditImpersonationStart, userId, map[string]interface{}{
+		"reason":    reason,
+		"sessionId": response.SessionID,
+		"expiresAt": response.ExpiresAt,
+	})
+	return response, nil
+}
+
+func (service *Service) RecordImpersonatedRequest(impersonatorId, userId uuid.UUID, client models.ClientInfo, method, path string, status int) {
+	service.record(impersonatorId, client, models.AuditImpersonatedRequest, userId, map[string]interface{}{
+		"method": method,
+		"path":   path,
+		"status": status,
+	})
+}
+
+func (service *Service) ListAuditLog(filter *models.AuditLogFilter, page, limit int) (*models.AuditLogPage, error) {
+	entries, total, err := service.repo.ListAuditEntries(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLogPage{Entries: entries, Total: total, Page: page, Limit: limit}, nil
+}
+
+// Helper methods
+
+// record writes an audit entry. The action has already happened, so a failure is logged
+// rather than returned.
+func (service *Service) record(adminId uuid.UUID, client models.ClientInfo, action string, userId uuid.UUID, details map[string]interface{}) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		encoded = []byte("{}")
+	}
+
+	entry := &models.AuditEntry{
+		ID:         uuid.New(),
+		AdminID:    &adminId,
+		Action:     action,
+		TargetType: models.AuditTargetUser,
+		TargetID:   &userId,
+		Details:    encoded,
+		IPAddress:  client.IP,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := service.repo.CreateAuditEntry(entry); err != nil {
+		logger.LogError("Error recording admin audit entry", err,
+			logger.Field("admin_id", adminId),
+			logger.Field("action", action),
+			logger.Field("target_id", userId),
+		)
+	}
+}
@@ -0,0 +1,102 @@
+package apikeys
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type Controller struct {
+	service IAPIKeyService
+}
+
+func NewController(service IAPIKeyService) *Controller {
+	return &Controller{service: service}
+}
+
+func (ctrl *Controller) ListKeys(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	keys, err := ctrl.service.ListKeys(user.Id)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch API keys", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, keys)
+}
+
+func (ctrl *Controller) CreateKey(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	var req models.APIKeyRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	key, err := ctrl.service.CreateKey(user.Id, &req)
+	if err != nil {
+		return logger.Error(ctx, "Failed to create API key", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusCreated, key)
+}
+
+func (ctrl *Controller) RotateKey(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	keyId, err := uuid.Parse(ctx.Param("keyId"))
+	if err != nil {
+		return errors.ValidationError("Invalid API key ID")
+	}
+
+	key, err := ctrl.service.RotateKey(user.Id, keyId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to rotate API key", err,
+			logger.Field("api_key_id", keyId),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, key)
+}
+
+func (ctrl *Controller) RevokeKey(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	keyId, err := uuid.Parse(ctx.Param("keyId"))
+	if err != nil {
+		return errors.ValidationError("Invalid API key ID")
+	}
+
+	if err := ctrl.service.RevokeKey(user.Id, keyId); err != nil {
+		return logger.Error(ctx, "Failed to revoke API key", err,
+			logger.Field("api_key_id", keyId),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package apikeys
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type IAPIKeyRepository interface {
+	ListKeys(userId uuid.UUID) ([]models.APIKey, error)
+	GetKey(userId, keyId uuid.UUID) (*models.APIKey, error)
+	CreateKey(key *models.APIKey) error
+	RotateKey(keyId uuid.UUID, prefix, keyHash string) error
+	RevokeKey(keyId uuid.UUID) error
+}
+
+type IAPIKeyService interface {
+	ListKeys(userId uuid.UUID) ([]models.APIKey, error)
+	CreateKey(userId uuid.UUID, req *models.APIKeyRequest) (*models.APIKeyCreated, error)
+	RotateKey(userId, keyId uuid.UUID) (*models.APIKeyCreated, error)
+	RevokeKey(userId, keyId uuid.UUID) error
+}
+
+type IAPIKeyController interface {
+	ListKeys(ctx echo.Context) error
+	CreateKey(ctx echo.Context) error
+	RotateKey(ctx echo.Context) error
+	RevokeKey(ctx echo.Context) error
+}
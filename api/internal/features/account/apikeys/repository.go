@@ -0,0 +1,80 @@
+package apikeys
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) IAPIKeyRepository {
+	return &Repository{db: db}
+}
+
+func (repo *Repository) ListKeys(userId uuid.UUID) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	err := repo.db.Select(&keys, `
+		SELECT * FROM api_keys
+		WHERE user_id = $1
+		ORDER BY revoked_at IS NOT NULL, created_at DESC`, userId)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving API keys")
+	}
+
+	return keys, nil
+}
+
+func (repo *Repository) GetKey(userId, keyId uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	err := repo.db.Get(&key, `SELECT * FROM api_keys WHERE id = $1 AND user_id = $2`, keyId, userId)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundError("API key not found")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving API key")
+	}
+
+	return &key, nil
+}
+
+func (repo *Repository) CreateKey(key *models.APIKey) error {
+	_, err := repo.db.NamedExec(`
+		INSERT INTO api_keys (id, user_id, inventory_id, name, prefix, key_hash, scope, created_at, expires_at)
+		VALUES (:id, :user_id, :inventory_id, :name, :prefix, :key_hash, :scope, :created_at, :expires_at)`, key)
+	if err != nil {
+		return errors.DatabaseError(err, "Error creating API key")
+	}
+
+	return nil
+}
+
+// RotateKey replaces the secret of an active key; the old secret stops working immediately
+func (repo *Repository) RotateKey(keyId uuid.UUID, prefix, keyHash string) error {
+	result, err := repo.db.Exec(`
+		UPDATE api_keys SET prefix = $2, key_hash = $3, last_used_at = NULL
+		WHERE id = $1 AND revoked_at IS NULL`, keyId, prefix, keyHash)
+	if err != nil {
+		return errors.DatabaseError(err, "Error rotating API key")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.ConflictError("Revoked API keys cannot be rotated")
+	}
+
+	return nil
+}
+
+func (repo *Repository) RevokeKey(keyId uuid.UUID) error {
+	_, err := repo.db.Exec(`UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`,
+		keyId, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error revoking API key")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+)
+
+// Characters of the secret kept in clear so users can tell their keys apart
+const displayPrefixLength = len(models.APIKeyPrefix) + 8
+
+type Service struct {
+	repo   IAPIKeyRepository
+	access access.IAccessService
+}
+
+func NewService(repo IAPIKeyRepository, accessService access.IAccessService) IAPIKeyService {
+	return &Service{repo: repo, access: accessService}
+}
+
+func (service *Service) ListKeys(userId uuid.UUID) ([]models.APIKey, error) {
+	return service.repo.ListKeys(userId)
+}
+
+func (service *Service) CreateKey(userId uuid.UUID, req *models.APIKeyRequest) (*models.APIKeyCreated, error) {
+	// A key can never reach further than its owner
+	if req.InventoryID != nil {
+		if _, err := service.access.AuthorizeInventory(userId, *req.InventoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	secret, keyHash, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := models.APIKey{
+		ID:          uuid.New(),
+		UserID:      userId,
+		InventoryID: req.InventoryID,
+		Name:        req.Name,
+		Prefix:      secret[:displayPrefixLength],
+		KeyHash:     keyHash,
+		Scope:       req.Scope,
+		CreatedAt:   now,
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := now.AddDate(0, 0, *req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := service.repo.CreateKey(&key); err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyCreated{APIKey: key, Secret: secret}, nil
+}
+
+// RotateKey issues a new secret for an existing key, keeping its name, scope and expiry
+func (service *Service) RotateKey(userId, keyId uuid.UUID) (*models.APIKeyCreated, error) {
+	key, err := service.repo.GetKey(userId, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, keyHash, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repo.RotateKey(key.ID, secret[:displayPrefixLength], keyHash); err != nil {
+		return nil, err
+	}
+
+	key.Prefix = secret[:displayPrefixLength]
+	key.KeyHash = keyHash
+	key.LastUsedAt = nil
+	return &models.APIKeyCreated{APIKey: *key, Secret: secret}, nil
+}
+
+func (service *Service) RevokeKey(userId, keyId uuid.UUID) error {
+	key, err := service.repo.GetKey(userId, keyId)
+	if err != nil {
+		return err
+	}
+
+	return service.repo.RevokeKey(key.ID)
+}
+
+// newSecret returns a new API key secret and the hash it is stored under
+func newSecret() (string, string, error) {
+	raw, err := utils.GenerateToken()
+	if err != nil {
+		return "", "", errors.InternalError(err, "Error generating API key")
+	}
+
+	secret := models.APIKeyPrefix + raw
+	return secret, utils.HashToken(secret), nil
+}
@@ -2,9 +2,13 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
 	"github.com/venntry/pkg/logger"
 )
 
@@ -57,13 +61,18 @@ func (ctrl *Controller) Login(ctx echo.Context) error {
 		return logger.Error(ctx, "Validation failed", err)
 	}
 
-	response, err := ctrl.service.LoginUser(input.Email, input.Password)
+	response, challenge, err := ctrl.service.LoginUser(input.Email, input.Password, ClientInfo(ctx))
 	if err != nil {
 		return logger.Error(ctx, "Invalid email or password!", err,
 			logger.Field("email", input.Email),
 		)
 	}
 
+	// Second step still required
+	if challenge != nil {
+		return ctx.JSON(http.StatusOK, challenge)
+	}
+
 	logger.Info("User login successful!")
 	return ctx.JSON(http.StatusOK, response)
 }
@@ -88,3 +97,316 @@ func (ctrl *Controller) CheckTokenExpiration(ctx echo.Context) error {
 	isExpired := ctrl.service.IsTokenExpired(tokenString)
 	return ctx.JSON(http.StatusOK, map[string]bool{"expired": isExpired})
 }
+
+func (ctrl *Controller) RefreshToken(ctx echo.Context) error {
+	var input models.RefreshRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	response, err := ctrl.service.RefreshTokens(input.RefreshToken)
+	if err != nil {
+		return logger.Error(ctx, "Token refresh failed", err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl *Controller) Logout(ctx echo.Context) error {
+	sessionId, ok := ctx.Get("sessionId").(uuid.UUID)
+	if !ok {
+		return logger.Error(ctx, "Session not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	if err := ctrl.service.Logout(sessionId); err != nil {
+		return logger.Error(ctx, "Logout failed", err,
+			logger.Field("session_id", sessionId),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *Controller) ListSessions(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	sessionId, _ := ctx.Get("sessionId").(uuid.UUID)
+
+	sessions, err := ctrl.service.ListSessions(user.Id, sessionId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to retrieve sessions", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, sessions)
+}
+
+func (ctrl *Controller) TerminateSession(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	sessionId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid session ID")
+	}
+
+	if err := ctrl.service.TerminateSession(user.Id, sessionId); err != nil {
+		return logger.Error(ctx, "Failed to terminate session", err,
+			logger.Field("user_id", user.Id),
+			logger.Field("session_id", sessionId),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *Controller) ForgotPassword(ctx echo.Context) error {
+	var input models.ForgotPasswordRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	if err := ctrl.service.ForgotPassword(input.Email); err != nil {
+		return logger.Error(ctx, "Password reset request failed", err)
+	}
+
+	return ctx.JSON(http.StatusAccepted, map[string]string{
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+func (ctrl *Controller) ResetPassword(ctx echo.Context) error {
+	var input models.ResetPasswordRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	if err := ctrl.service.ResetPassword(input.Token, input.Password); err != nil {
+		return logger.Error(ctx, "Password reset failed", err)
+	}
+
+	logger.Info("Password reset successful!")
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Password has been reset"})
+}
+
+func (ctrl *Controller) VerifyEmail(ctx echo.Context) error {
+	var input models.VerifyEmailRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	if err := ctrl.service.VerifyEmail(input.Token); err != nil {
+		return logger.Error(ctx, "Email verification failed", err)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Email has been verified"})
+}
+
+func (ctrl *Controller) ResendVerification(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	if err := ctrl.service.ResendVerification(user); err != nil {
+		return logger.Error(ctx, "Resending verification email failed", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusAccepted, map[string]string{"message": "Verification email sent"})
+}
+
+func (ctrl *Controller) LoginTwoFactor(ctx echo.Context) error {
+	var input models.TwoFactorLoginRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	response, err := ctrl.service.CompleteTwoFactorLogin(input.ChallengeToken, input.Code, ClientInfo(ctx))
+	if err != nil {
+		return logger.Error(ctx, "Two-factor login failed", err)
+	}
+
+	logger.Info("User login successful!")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl *Controller) SetupTwoFactor(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	response, err := ctrl.service.SetupTwoFactor(user)
+	if err != nil {
+		return logger.Error(ctx, "Two-factor setup failed", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl *Controller) EnableTwoFactor(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	var input models.TwoFactorCodeRequest
+	if err := utils.BindAndValidateRequest(ctx, &input); err != nil {
+		return err
+	}
+
+	codes, err := ctrl.service.EnableTwoFactor(user, input.Code)
+	if err != nil {
+		return logger.Error(ctx, "Enabling two-factor authentication failed", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (ctrl *Controller) DisableTwoFactor(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	var input models.TwoFactorDisableRequest
+	if err := utils.BindAndValidateRequest(ctx, &input); err != nil {
+		return err
+	}
+
+	if err := ctrl.service.DisableTwoFactor(user, input.Password, input.Code); err != nil {
+		return logger.Error(ctx, "Disabling two-factor authentication failed", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *Controller) RegenerateRecoveryCodes(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	var input models.TwoFactorCodeRequest
+	if err := utils.BindAndValidateRequest(ctx, &input); err != nil {
+		return err
+	}
+
+	codes, err := ctrl.service.RegenerateRecoveryCodes(user, input.Code)
+	if err != nil {
+		return logger.Error(ctx, "Regenerating recovery codes failed", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (ctrl *Controller) UnlockAccount(ctx echo.Context) error {
+	var input models.UnlockAccountRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	if err := ctrl.service.UnlockAccount(input.Token); err != nil {
+		return logger.Error(ctx, "Account unlock failed", err)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Account has been unlocked"})
+}
+
+func (ctrl *Controller) OIDCAuthorize(ctx echo.Context) error {
+	response, err := ctrl.service.OIDCAuthorizationURL(ctx.Request().Context())
+	if err != nil {
+		return logger.Error(ctx, "Single sign-on is unavailable", err)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl *Controller) OIDCCallback(ctx echo.Context) error {
+	var input models.OIDCCallbackRequest
+
+	if err := ctx.Bind(&input); err != nil {
+		return logger.Error(ctx, "Invalid input", err)
+	}
+
+	if err := ctx.Validate(&input); err != nil {
+		return logger.Error(ctx, "Validation failed", err)
+	}
+
+	response, challenge, err := ctrl.service.LoginWithOIDC(ctx.Request().Context(), input.Code, input.State, ClientInfo(ctx))
+	if err != nil {
+		return logger.Error(ctx, "Single sign-on failed", err)
+	}
+
+	// Second step still required
+	if challenge != nil {
+		return ctx.JSON(http.StatusOK, challenge)
+	}
+
+	logger.Info("User login successful!")
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// ClientInfo identifies the device behind a login request
+func ClientInfo(ctx echo.Context) models.ClientInfo {
+	userAgent := ctx.Request().UserAgent()
+	if len(userAgent) > 512 {
+		userAgent = strings.ToValidUTF8(userAgent[:512], "")
+	}
+
+	return models.ClientInfo{IP: ctx.RealIP(), UserAgent: userAgent}
+}
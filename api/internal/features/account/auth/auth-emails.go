@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/venntry/pkg/mailer"
+)
+
+func passwordResetEmail(to, username, link string) *mailer.Message {
+	return &mailer.Message{
+		To:      to,
+		Subject: "Reset your Venntry password",
+		Body: fmt.Sprintf(`Hi %s,
+
+We received a request to reset the password for your Venntry account.
+Use the link below to choose a new password. It expires in %s and can only be used once.
+
+%s
+
+If you did not request a password reset, you can ignore this email.
+`, username, passwordResetTTL, link),
+	}
+}
+
+func verificationEmail(to, username, link string) *mailer.Message {
+	return &mailer.Message{
+		To:      to,
+		Subject: "Verify your Venntry email address",
+		Body: fmt.Sprintf(`Hi %s,
+
+Please confirm your email address by opening the link below. It expires in %s.
+
+%s
+
+If you did not create a Venntry account, you can ignore this email.
+`, username, emailVerificationTTL, link),
+	}
+}
+
+func accountLockedEmail(to, username, link string) *mailer.Message {
+	return &mailer.Message{
+		To:      to,
+		Subject: "Your Venntry account has been locked",
+		Body: fmt.Sprintf(`Hi %s,
+
+We temporarily locked your Venntry account after too many failed sign-in attempts.
+It unlocks automatically in %s, or right away with the link below.
+
+%s
+
+If these attempts were not yours, consider resetting your password once you are back in.
+`, username, lockoutDuration, link),
+	}
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -11,10 +13,38 @@ type IAuthRepository interface {
 	CreateUser(input *models.UserRequest) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(id uuid.UUID) (*models.User, error)
-	GetUserInventories(userId uuid.UUID) ([]models.Inventory, error)
+	GetUserInventories(userId uuid.UUID) ([]models.UserInventory, error)
 
 	UsernameExists(username string) (bool, error)
 	EmailExists(email string) (bool, error)
+
+	CreateSession(session *models.AuthSession, token *models.RefreshToken) error
+	GetSession(sessionId uuid.UUID) (*models.AuthSession, error)
+	GetActiveSessions(userId uuid.UUID) ([]models.AuthSession, error)
+	TouchSession(sessionId uuid.UUID) error
+	RotateRefreshToken(tokenHash string, next *models.RefreshToken) (*models.AuthSession, bool, error)
+	RevokeSession(sessionId uuid.UUID, reason string) error
+	RevokeUserSessions(userId, keep uuid.UUID, reason string) ([]uuid.UUID, error)
+
+	GetAPIKeyByHash(keyHash string) (*models.APIKey, error)
+	TouchAPIKey(keyId uuid.UUID) error
+
+	GetTwoFactor(userId uuid.UUID) (*models.TwoFactor, error)
+	SaveTwoFactorSecret(userId uuid.UUID, secret string) error
+	EnableTwoFactor(userId uuid.UUID, step int64, codeHashes []string) error
+	ReplaceRecoveryCodes(userId uuid.UUID, codeHashes []string) error
+	UseTwoFactorStep(userId uuid.UUID, step int64) (bool, error)
+	UseRecoveryCode(userId uuid.UUID, codeHash string) (bool, error)
+	DisableTwoFactor(userId uuid.UUID) error
+
+	GetUserByIdentity(issuer, subject string) (*models.User, error)
+	SaveIdentity(identity *models.UserIdentity) error
+	MarkEmailVerified(userId uuid.UUID) error
+
+	CreateUserToken(token *models.UserToken) error
+	ConsumeUserToken(tokenHash, purpose string) (*models.UserToken, error)
+	ResetPassword(tokenHash, passwordHash string) ([]uuid.UUID, error)
+	VerifyEmail(tokenHash string) error
 }
 
 type IAuthController interface {
@@ -22,12 +52,50 @@ type IAuthController interface {
 	Login(ctx echo.Context) error
 	GetProfile(ctx echo.Context) error
 	CheckTokenExpiration(ctx echo.Context) error
+	RefreshToken(ctx echo.Context) error
+	Logout(ctx echo.Context) error
+	ListSessions(ctx echo.Context) error
+	TerminateSession(ctx echo.Context) error
+	ForgotPassword(ctx echo.Context) error
+	ResetPassword(ctx echo.Context) error
+	VerifyEmail(ctx echo.Context) error
+	ResendVerification(ctx echo.Context) error
+	UnlockAccount(ctx echo.Context) error
+	LoginTwoFactor(ctx echo.Context) error
+	OIDCAuthorize(ctx echo.Context) error
+	OIDCCallback(ctx echo.Context) error
+	SetupTwoFactor(ctx echo.Context) error
+	EnableTwoFactor(ctx echo.Context) error
+	DisableTwoFactor(ctx echo.Context) error
+	RegenerateRecoveryCodes(ctx echo.Context) error
 }
 
 type IAuthService interface {
 	RegisterUser(username, email, password string) (*models.User, error)
-	LoginUser(email, password string) (*models.UserResponse, error)
+	LoginUser(email, password string, client models.ClientInfo) (*models.UserResponse, *models.TwoFactorChallenge, error)
+	CompleteTwoFactorLogin(challengeToken, code string, client models.ClientInfo) (*models.UserResponse, error)
+	OIDCAuthorizationURL(ctx context.Context) (*models.OIDCAuthorizeResponse, error)
+	LoginWithOIDC(ctx context.Context, code, state string, client models.ClientInfo) (*models.UserResponse, *models.TwoFactorChallenge, error)
 	ValidateToken(tokenString string) (*jwt.Token, error)
 	IsTokenExpired(tokenString string) bool
-	GetUserFromToken(tokenString string) (*models.User, error)
+	GetUserFromToken(tokenString string) (*models.User, *models.SessionClaims, error)
+	GetUserFromAPIKey(secret string) (*models.User, *models.APIKey, error)
+	RefreshTokens(refreshToken string) (*models.TokenResponse, error)
+	Logout(sessionId uuid.UUID) error
+	ListSessions(userId, currentSessionId uuid.UUID) ([]models.SessionResponse, error)
+	TerminateSession(userId, sessionId uuid.UUID) error
+	RevokeUserSessions(userId, keep uuid.UUID, reason string) error
+	ForgotPassword(email string) error
+	SendPasswordReset(userId uuid.UUID) error
+	ResetPassword(token, password string) error
+	VerifyEmail(token string) error
+	ResendVerification(user *models.User) error
+	UnlockAccount(token string) error
+	UnlockUser(userId uuid.UUID) error
+	Impersonate(admin *models.User, userId uuid.UUID, client models.ClientInfo) (*models.ImpersonationResponse, error)
+
+	SetupTwoFactor(user *models.User) (*models.TwoFactorSetupResponse, error)
+	EnableTwoFactor(user *models.User, code string) ([]string, error)
+	DisableTwoFactor(user *models.User, password, code string) error
+	RegenerateRecoveryCodes(user *models.User, code string) ([]string, error)
 }
@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/venntry/internal/models"
 	"github.com/venntry/pkg/errors"
 	"github.com/venntry/pkg/logger"
@@ -23,17 +26,107 @@ func AuthMiddleware(service IAuthService) echo.MiddlewareFunc {
 			}
 
 			token := tokenParts[1]
-			user, err := service.GetUserFromToken(token)
+			if strings.HasPrefix(token, models.APIKeyPrefix) {
+				return authenticateAPIKey(ctx, service, token, next)
+			}
+
+			user, session, err := service.GetUserFromToken(token)
 			if err != nil {
 				return echo.NewHTTPError(401, "invalid auth token")
 			}
 
 			ctx.Set("user", user)
+			ctx.Set("sessionId", session.SessionID)
+			if session.ImpersonatorID != nil {
+				ctx.Set("impersonatorId", *session.ImpersonatorID)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// authenticateAPIKey signs in a machine client. The user is set as for JWTs, plus "apiKey"
+// so later middleware can apply the key's restrictions; there is no session.
+func authenticateAPIKey(ctx echo.Context, service IAuthService, secret string, next echo.HandlerFunc) error {
+	user, key, err := service.GetUserFromAPIKey(secret)
+	if err != nil {
+		return echo.NewHTTPError(401, "invalid API key")
+	}
+
+	if key.Scope == models.ScopeRead && !isReadOnlyMethod(ctx.Request().Method) {
+		return logger.Error(ctx, "Access forbidden: read-only API key",
+			errors.ForbiddenError("This API key is read-only"),
+			logger.Field("api_key_id", key.ID),
+		)
+	}
+
+	ctx.Set("user", user)
+	ctx.Set("apiKey", key)
+	return next(ctx)
+}
+
+// RequireSession rejects API keys and impersonation sessions on routes that manage the
+// account itself, such as changing the password or creating more keys. It must run after
+// AuthMiddleware.
+func RequireSession() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if _, ok := ctx.Get("apiKey").(*models.APIKey); ok {
+				return logger.Error(ctx, "Access forbidden: API key used on account route",
+					errors.ForbiddenError("This action requires signing in with a password"))
+			}
+
+			if impersonatorId, ok := ctx.Get("impersonatorId").(uuid.UUID); ok {
+				return logger.Error(ctx, "Access forbidden: impersonation session used on account route",
+					errors.ForbiddenError("This action is not available while impersonating a user"),
+					logger.Field("impersonator_id", impersonatorId),
+				)
+			}
+
 			return next(ctx)
 		}
 	}
 }
 
+// RejectInventoryKeys rejects keys bound to a single inventory on routes that are not scoped
+// to one, such as listing or creating inventories. It must run after AuthMiddleware.
+func RejectInventoryKeys() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if key, ok := ctx.Get("apiKey").(*models.APIKey); ok && key.InventoryID != nil {
+				return logger.Error(ctx, "Access forbidden: inventory API key used outside its inventory",
+					errors.ForbiddenError("This API key is limited to a single inventory"),
+					logger.Field("api_key_id", key.ID),
+				)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// RateLimitMiddleware throttles a route group. Requests are counted per user when the caller
+// is authenticated, so it must run after AuthMiddleware on protected groups, and per IP
+// otherwise. Each group has its own budget.
+func RateLimitMiddleware(store middleware.RateLimiterStore, group string) echo.MiddlewareFunc {
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: store,
+		IdentifierExtractor: func(ctx echo.Context) (string, error) {
+			if user, ok := ctx.Get("user").(*models.User); ok {
+				return "rate:" + group + ":user:" + user.Id.String(), nil
+			}
+			return "rate:" + group + ":ip:" + ctx.RealIP(), nil
+		},
+		DenyHandler: func(ctx echo.Context, identifier string, err error) error {
+			return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+		},
+	})
+}
+
 func RoleMiddleware(roles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
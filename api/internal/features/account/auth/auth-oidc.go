@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
+	"github.com/venntry/config"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+// How long a user has to complete the login at the provider
+const oidcStateTTL = 10 * time.Minute
+
+var usernameUnsafe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// oidcState is the cached half of an authorization request, looked up by its state parameter
+type oidcState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+type oidcClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+func oidcStateKey(state string) string {
+	return "oidc:state:" + utils.HashToken(state)
+}
+
+// oidcClient discovers the provider on first use, so the API can start while the identity
+// provider is unreachable
+type oidcClient struct {
+	cfg *config.Variables
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func newOIDCClient(cfg *config.Variables) *oidcClient {
+	if cfg == nil || cfg.OIDCIssuerURL == "" {
+		return nil
+	}
+	return &oidcClient{cfg: cfg}
+}
+
+func (client *oidcClient) load(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.oauth != nil {
+		return client.oauth, client.verifier, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, client.cfg.OIDCIssuerURL)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.InternalErr, "Identity provider is unavailable", 502)
+	}
+
+	client.oauth = &oauth2.Config{
+		ClientID:     client.cfg.OIDCClientID,
+		ClientSecret: client.cfg.OIDCClientSecret,
+		RedirectURL:  client.cfg.OIDCRedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       client.cfg.OIDCScopes,
+	}
+	client.verifier = provider.Verifier(&oidc.Config{ClientID: client.cfg.OIDCClientID})
+
+	return client.oauth, client.verifier, nil
+}
+
+// OIDCAuthorizationURL starts an authorization code flow with PKCE and returns the provider
+// URL to send the user to
+func (service *Service) OIDCAuthorizationURL(ctx context.Context) (*models.OIDCAuthorizeResponse, error) {
+	if service.oidc == nil {
+		return nil, errors.NotFoundError("Single sign-on is not configured")
+	}
+
+	oauth, _, err := service.oidc.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GenerateToken()
+	if err != nil {
+		return nil, errors.InternalError(err, "Error generating login state")
+	}
+	nonce, err := utils.GenerateToken()
+	if err != nil {
+		return nil, errors.InternalError(err, "Error generating login nonce")
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	if err := service.cache.Set(oidcStateKey(state), oidcState{Verifier: verifier, Nonce: nonce}, oidcStateTTL); err != nil {
+		return nil, errors.CacheError(err, "Error storing login state")
+	}
+
+	return &models.OIDCAuthorizeResponse{
+		AuthorizationURL: oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)),
+		ExpiresAt:        time.Now().Add(oidcStateTTL),
+	}, nil
+}
+
+// LoginWithOIDC completes the flow: the code is exchanged, the ID token verified, and the
+// identity resolved to a Venntry user, which is linked by verified email or provisioned.
+func (service *Service) LoginWithOIDC(ctx context.Context, code, state string, client models.ClientInfo) (*models.UserResponse, *models.TwoFactorChallenge, error) {
+	if service.oidc == nil {
+		return nil, nil, errors.NotFoundError("Single sign-on is not configured")
+	}
+
+	oauth, verifier, err := service.oidc.load(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Each state is good for a single attempt, even when callbacks race
+	var pending oidcState
+	if err := service.cache.GetDel(oidcStateKey(state), &pending); err != nil {
+		return nil, nil, errors.UnauthorizedError("Login state is invalid or has expired")
+	}
+
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(pending.Verifier))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.Unauthorized, "Authorization code exchange failed", 401)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, nil, errors.UnauthorizedError("Identity provider did not return an ID token")
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.Unauthorized, "Invalid ID token", 401)
+	}
+	if idToken.Nonce != pending.Nonce {
+		return nil, nil, errors.UnauthorizedError("Invalid ID token nonce")
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, nil, errors.Wrap(err, errors.Unauthorized, "Invalid ID token claims", 401)
+	}
+
+	user, err := service.resolveOIDCUser(idToken.Issuer, idToken.Subject, &claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkNotSuspended(user); err != nil {
+		return nil, nil, err
+	}
+
+	twoFactor, err := service.repo.GetTwoFactor(user.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		challenge, err := service.newChallenge(user.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	response, err := service.issueLogin(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// resolveOIDCUser finds the user for an external identity. Unknown identities are linked to
+// the account with the same email, or a new account is created; both require the provider
+// to have verified the email. An existing account must have verified it too, or whoever
+// registered the address with a password of their own would share the account.
+func (service *Service) resolveOIDCUser(issuer, subject string, claims *oidcClaims) (*models.User, error) {
+	user, err := service.repo.GetUserByIdentity(issuer, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		email := normalizeEmail(claims.Email)
+		if email == "" || !claims.EmailVerified {
+			return nil, errors.ForbiddenError("Identity provider did not supply a verified email address")
+		}
+
+		exists, err := service.repo.EmailExists(email)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			user, err = service.repo.GetUserByEmail(email)
+			if err != nil {
+				return nil, err
+			}
+			if user.EmailVerifiedAt == nil {
+				return nil, errors.ConflictError("An account with this email exists but has not verified it; sign in with its password and verify the email before using single sign-on")
+			}
+		} else {
+			user, err = service.provisionOIDCUser(email, claims)
+			if err != nil {
+				return nil, err
+			}
+			if err := service.repo.MarkEmailVerified(user.Id); err != nil {
+				return nil, err
+			}
+		}
+
+		logger.Info("Linked external identity", logger.Field("user_id", user.Id), logger.Field("issuer", issuer))
+	}
+
+	now := time.Now()
+	err = service.repo.SaveIdentity(&models.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      user.Id,
+		Issuer:      issuer,
+		Subject:     subject,
+		Email:       normalizeEmail(claims.Email),
+		CreatedAt:   now,
+		LastLoginAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// provisionOIDCUser creates an account for a first-time single sign-on user. Its password is
+// random and unknown, so signing in with a password requires a reset first.
+func (service *Service) provisionOIDCUser(email string, claims *oidcClaims) (*models.User, error) {
+	username, err := service.availableUsername(claims.PreferredUsername, email)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateToken()
+	if err != nil {
+		return nil, errors.InternalError(err, "Error generating password")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.InternalError(err, "Error hashing password")
+	}
+
+	return service.repo.CreateUser(&models.UserRequest{
+		Username: username,
+		Email:    email,
+		Password: string(hashedPassword),
+		Role:     "user",
+	})
+}
+
+// availableUsername derives a free username from the provider's preferred username or the
+// email's local part
+func (service *Service) availableUsername(preferred, email string) (string, error) {
+	base := strings.ToLower(strings.TrimSpace(preferred))
+	if base == "" || strings.Contains(base, "@") {
+		base = strings.ToLower(strings.SplitN(email, "@", 2)[0])
+	}
+	base = strings.Trim(usernameUnsafe.ReplaceAllString(base, ""), "._-")
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		exists, err := service.repo.UsernameExists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = base + "-" + uuid.NewString()[:6]
+	}
+
+	return "", errors.ConflictError("Could not find an available username")
+}
@@ -0,0 +1,556 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	josejwt "github.com/go-jose/go-jose/v4/jwt"
+	"github.com/google/uuid"
+	"github.com/venntry/config"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/cache"
+	"github.com/venntry/pkg/errors"
+)
+
+const (
+	testClientID = "venntry"
+	testKeyID    = "test-key"
+)
+
+// mockIssuer is a minimal OpenID provider: discovery, JWKS and a token endpoint that enforces
+// PKCE. Codes are handed out by the test in place of a browser round trip.
+type mockIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	signer jose.Signer
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockGrant
+}
+
+// mockGrant is what the provider remembers about an authorization code
+type mockGrant struct {
+	challenge string
+	nonce     string
+	subject   string
+	email     string
+	verified  bool
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", testKeyID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &mockIssuer{t: t, signer: signer, key: key, codes: map[string]mockGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (m *mockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                m.server.URL,
+		"authorization_endpoint":                m.server.URL + "/authorize",
+		"token_endpoint":                        m.server.URL + "/token",
+		"jwks_uri":                              m.server.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (m *mockIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &m.key.PublicKey,
+		KeyID:     testKeyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}})
+}
+
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	grant, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	digest := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(digest[:]) != grant.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken, err := josejwt.Signed(m.signer).Claims(josejwt.Claims{
+		Issuer:   m.server.URL,
+		Subject:  grant.subject,
+		Audience: josejwt.Audience{testClientID},
+		IssuedAt: josejwt.NewNumericDate(now),
+		Expiry:   josejwt.NewNumericDate(now.Add(time.Hour)),
+	}).Claims(map[string]interface{}{
+		"nonce":          grant.nonce,
+		"email":          grant.email,
+		"email_verified": grant.verified,
+	}).Serialize()
+	if err != nil {
+		m.t.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access-" + uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// authorize stands in for the user signing in at the provider: it issues a code bound to the
+// authorization request behind authURL, with the grant's identity
+func (m *mockIssuer) authorize(authURL string, grant mockGrant) (code, state string) {
+	m.t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		m.t.Fatalf("authorization URL does not use S256 PKCE: %s", authURL)
+	}
+
+	if grant.challenge == "" {
+		grant.challenge = query.Get("code_challenge")
+	}
+	if grant.nonce == "" {
+		grant.nonce = query.Get("nonce")
+	}
+
+	code = uuid.NewString()
+	m.mu.Lock()
+	m.codes[code] = grant
+	m.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+// memoryCache implements cache.IRedisService in memory
+type memoryCache struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{items: map[string][]byte{}}
+}
+
+func (c *memoryCache) Get(key string, dest interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, ok := c.items[key]
+	if !ok {
+		return cache.ErrCacheMiss
+	}
+	return json.Unmarshal(val, dest)
+}
+
+func (c *memoryCache) GetDel(key string, dest interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, ok := c.items[key]
+	if !ok {
+		return cache.ErrCacheMiss
+	}
+	delete(c.items, key)
+	return json.Unmarshal(val, dest)
+}
+
+func (c *memoryCache) Set(key string, value interface{}, expiration time.Duration) error {
+	val, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = val
+	return nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+	return nil
+}
+
+func (c *memoryCache) Increment(key string, expiration time.Duration) (int64, error) {
+	var count int64
+	c.Get(key, &count)
+	count++
+	return count, c.Set(key, count, expiration)
+}
+
+// memoryRepo implements the parts of IAuthRepository the single sign-on flow uses; calling
+// anything else panics on the nil embedded interface
+type memoryRepo struct {
+	IAuthRepository
+
+	mu         sync.Mutex
+	users      map[uuid.UUID]*models.User
+	identities map[string]uuid.UUID
+	sessions   int
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{users: map[uuid.UUID]*models.User{}, identities: map[string]uuid.UUID{}}
+}
+
+func (r *memoryRepo) addUser(email string, verified bool) *models.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := &models.User{Id: uuid.New(), Username: strings.Split(email, "@")[0], Email: email, Role: "user"}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	r.users[user.Id] = user
+	return user
+}
+
+func (r *memoryRepo) CreateUser(input *models.UserRequest) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := &models.User{Id: uuid.New(), Username: input.Username, Email: input.Email, Password: input.Password, Role: input.Role}
+	r.users[user.Id] = user
+	return user, nil
+}
+
+func (r *memoryRepo) GetUserByEmail(email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errors.NotFoundError("User not found")
+}
+
+func (r *memoryRepo) EmailExists(email string) (bool, error) {
+	_, err := r.GetUserByEmail(email)
+	return err == nil, nil
+}
+
+func (r *memoryRepo) UsernameExists(username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRepo) GetUserByIdentity(issuer, subject string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.identities[issuer+" "+subject]; ok {
+		return r.users[id], nil
+	}
+	return nil, nil
+}
+
+func (r *memoryRepo) SaveIdentity(identity *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.identities[identity.Issuer+" "+identity.Subject] = identity.UserID
+	return nil
+}
+
+func (r *memoryRepo) MarkEmailVerified(userId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.users[userId].EmailVerifiedAt = &now
+	return nil
+}
+
+func (r *memoryRepo) GetTwoFactor(userId uuid.UUID) (*models.TwoFactor, error) {
+	return nil, nil
+}
+
+func (r *memoryRepo) CreateSession(session *models.AuthSession, token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions++
+	return nil
+}
+
+func (r *memoryRepo) GetUserInventories(userId uuid.UUID) ([]models.UserInventory, error) {
+	return nil, nil
+}
+
+func newOIDCTestService(t *testing.T) (*Service, *memoryRepo, *mockIssuer) {
+	t.Helper()
+
+	issuer := newMockIssuer(t)
+	repo := newMemoryRepo()
+	cfg := &config.Variables{
+		JWTSecret:        "test-secret",
+		OIDCIssuerURL:    issuer.server.URL,
+		OIDCClientID:     testClientID,
+		OIDCClientSecret: "client-secret",
+		OIDCRedirectURL:  "http://localhost:3000/sso/callback",
+		OIDCScopes:       []string{"openid", "email", "profile"},
+	}
+
+	service := NewService(repo, newMemoryCache(), nil, cfg).(*Service)
+	return service, repo, issuer
+}
+
+// startLogin begins a flow and has the mock provider issue a code for it
+func startLogin(t *testing.T, service *Service, issuer *mockIssuer, grant mockGrant) (code, state string) {
+	t.Helper()
+
+	authorize, err := service.OIDCAuthorizationURL(context.Background())
+	if err != nil {
+		t.Fatalf("OIDCAuthorizationURL: %v", err)
+	}
+	return issuer.authorize(authorize.AuthorizationURL, grant)
+}
+
+func errorCode(err error) int {
+	if appErr, ok := err.(*errors.AppError); ok {
+		return appErr.Code
+	}
+	return 0
+}
+
+func TestOIDCLoginProvisionsVerifiedUser(t *testing.T) {
+	service, repo, issuer := newOIDCTestService(t)
+
+	code, state := startLogin(t, service, issuer, mockGrant{subject: "sub-1", email: "Ada@Example.com", verified: true})
+	response, challenge, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if err != nil {
+		t.Fatalf("LoginWithOIDC: %v", err)
+	}
+	if challenge != nil || response == nil || response.Token == "" || response.RefreshToken == "" {
+		t.Fatalf("expected a completed login, got response %+v challenge %+v", response, challenge)
+	}
+	if response.Email != "ada@example.com" {
+		t.Errorf("email = %q, want the normalized provider email", response.Email)
+	}
+	if len(repo.users) != 1 || len(repo.identities) != 1 || repo.sessions != 1 {
+		t.Errorf("users %d, identities %d, sessions %d; want 1 each", len(repo.users), len(repo.identities), repo.sessions)
+	}
+	for _, user := range repo.users {
+		if user.EmailVerifiedAt == nil {
+			t.Error("an account provisioned from a verified provider email should be verified")
+		}
+	}
+}
+
+func TestOIDCLoginRejectsCodeWithoutMatchingVerifier(t *testing.T) {
+	service, repo, issuer := newOIDCTestService(t)
+
+	// A code bound to another authorization request, as in a code injection attack
+	code, state := startLogin(t, service, issuer, mockGrant{
+		challenge: "challenge-of-another-request",
+		subject:   "sub-1",
+		email:     "ada@example.com",
+		verified:  true,
+	})
+	_, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if errorCode(err) != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a failed PKCE exchange, got %v", err)
+	}
+	if len(repo.users) != 0 {
+		t.Error("no user may be created when the exchange fails")
+	}
+}
+
+func TestOIDCLoginRejectsNonceMismatch(t *testing.T) {
+	service, repo, issuer := newOIDCTestService(t)
+
+	code, state := startLogin(t, service, issuer, mockGrant{
+		nonce:    "replayed-nonce",
+		subject:  "sub-1",
+		email:    "ada@example.com",
+		verified: true,
+	})
+	_, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if errorCode(err) != http.StatusUnauthorized || !strings.Contains(err.Error(), "nonce") {
+		t.Fatalf("expected a nonce error, got %v", err)
+	}
+	if len(repo.users) != 0 {
+		t.Error("no user may be created for a token with the wrong nonce")
+	}
+}
+
+func TestOIDCLoginRejectsUnverifiedEmail(t *testing.T) {
+	service, repo, issuer := newOIDCTestService(t)
+	existing := repo.addUser("ada@example.com", true)
+
+	code, state := startLogin(t, service, issuer, mockGrant{subject: "sub-1", email: "ada@example.com", verified: false})
+	_, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if errorCode(err) != http.StatusForbidden {
+		t.Fatalf("expected 403 for an unverified email, got %v", err)
+	}
+	if len(repo.identities) != 0 || len(repo.users) != 1 {
+		t.Errorf("an unverified email must neither link %s nor create a user", existing.Id)
+	}
+}
+
+func TestOIDCLoginLinksExistingUserByEmail(t *testing.T) {
+	service, repo, issuer := newOIDCTestService(t)
+	existing := repo.addUser("ada@example.com", true)
+
+	code, state := startLogin(t, service, issuer, mockGrant{subject: "sub-1", email: "ada@example.com", verified: true})
+	response, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if err != nil {
+		t.Fatalf("LoginWithOIDC: %v", err)
+	}
+	if response.UserId != existing.Id.String() {
+		t.Errorf("logged in as %s, want the existing user %s", response.UserId, existing.Id)
+	}
+	if len(repo.users) != 1 {
+		t.Errorf("users = %d, want no new account", len(repo.users))
+	}
+	if repo.identities[issuer.server.URL+" sub-1"] != existing.Id {
+		t.Error("identity was not linked to the existing user")
+	}
+
+	// The next login resolves through the identity even if the provider email changes
+	code, state = startLogin(t, service, issuer, mockGrant{subject: "sub-1", email: "ada@elsewhere.com", verified: true})
+	response, _, err = service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if err != nil || response.UserId != existing.Id.String() {
+		t.Fatalf("second login = %+v, %v; want the linked user", response, err)
+	}
+}
+
+func TestOIDCLoginRefusesToLinkUnverifiedAccount(t *testing.T) {
+	service, repo, issuer := newOIDCTestService(t)
+
+	// Anyone can register an address with a password of their choosing; until its owner
+	// verifies it, single sign-on must not hand them the account
+	squatted := repo.addUser("ada@example.com", false)
+
+	code, state := startLogin(t, service, issuer, mockGrant{subject: "sub-1", email: "ada@example.com", verified: true})
+	_, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{})
+	if errorCode(err) != http.StatusConflict {
+		t.Fatalf("expected 409 when linking an unverified account, got %v", err)
+	}
+	if len(repo.identities) != 0 || repo.sessions != 0 {
+		t.Error("no identity may be linked and no session issued")
+	}
+	if squatted.EmailVerifiedAt != nil {
+		t.Error("the unverified account must stay unverified")
+	}
+}
+
+func TestOIDCStateIsSingleUse(t *testing.T) {
+	service, _, issuer := newOIDCTestService(t)
+	grant := mockGrant{subject: "sub-1", email: "ada@example.com", verified: true}
+
+	authorize, err := service.OIDCAuthorizationURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := issuer.authorize(authorize.AuthorizationURL, grant)
+	if _, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{}); err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+
+	// A fresh code for the same request must not get past the consumed state
+	code, _ = issuer.authorize(authorize.AuthorizationURL, grant)
+	if _, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{}); errorCode(err) != http.StatusUnauthorized {
+		t.Fatalf("expected 401 when reusing a state, got %v", err)
+	}
+}
+
+func TestOIDCStateConsumedOnceUnderConcurrentCallbacks(t *testing.T) {
+	service, _, issuer := newOIDCTestService(t)
+	grant := mockGrant{subject: "sub-1", email: "ada@example.com", verified: true}
+
+	authorize, err := service.OIDCAuthorizationURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const callbacks = 8
+	codes := make([]string, callbacks)
+	var state string
+	for i := range codes {
+		codes[i], state = issuer.authorize(authorize.AuthorizationURL, grant)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for _, code := range codes {
+		wg.Add(1)
+		go func(code string) {
+			defer wg.Done()
+			if _, _, err := service.LoginWithOIDC(context.Background(), code, state, models.ClientInfo{}); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(code)
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("%d callbacks succeeded with one state, want exactly 1", succeeded)
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -63,7 +64,7 @@ func (repo *Repository) CreateUser(input *models.UserRequest) (*models.User, err
 
 func (repo *Repository) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
-	query := `SELECT id, username, email, password, role, avatar, created_at, updated_at 
+	query := `SELECT id, username, email, password, role, avatar, avatar_key, email_verified_at, suspended_at, suspended_reason, created_at, updated_at
 			  FROM users WHERE email = $1`
 
 	err := repo.db.Get(&user, query, email)
@@ -76,7 +77,7 @@ func (repo *Repository) GetUserByEmail(email string) (*models.User, error) {
 
 func (repo *Repository) GetUserByID(id uuid.UUID) (*models.User, error) {
 	var user models.User
-	query := `SELECT id, username, email, password, role, avatar, created_at, updated_at 
+	query := `SELECT id, username, email, password, role, avatar, avatar_key, email_verified_at, suspended_at, suspended_reason, created_at, updated_at
 			  FROM users WHERE id = $1`
 
 	err := repo.db.Get(&user, query, id)
@@ -87,10 +88,13 @@ func (repo *Repository) GetUserByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
-func (repo *Repository) GetUserInventories(userId uuid.UUID) ([]models.Inventory, error) {
-	var inventories []models.Inventory
-	query := `SELECT id, name, user_id, created_at, updated_at 
-			  FROM inventories WHERE user_id = $1`
+func (repo *Repository) GetUserInventories(userId uuid.UUID) ([]models.UserInventory, error) {
+	var inventories []models.UserInventory
+	query := `SELECT i.id, i.name, i.user_id, i.created_at, i.updated_at, m.role
+			  FROM inventories i
+			  INNER JOIN inventory_members m ON m.inventory_id = i.id
+			  WHERE m.user_id = $1
+			  ORDER BY i.created_at ASC`
 
 	err := repo.db.Select(&inventories, query, userId)
 	if err != nil {
@@ -123,3 +127,559 @@ func (repo *Repository) EmailExists(email string) (bool, error) {
 
 	return exists, nil
 }
+
+func (repo *Repository) CreateSession(session *models.AuthSession, token *models.RefreshToken) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec(`
+		INSERT INTO auth_sessions (id, user_id, user_agent, ip_address, impersonator_id, created_at, last_used_at, expires_at)
+		VALUES (:id, :user_id, :user_agent, :ip_address, :impersonator_id, :created_at, :last_used_at, :expires_at)`, session)
+	if err != nil {
+		return errors.DatabaseError(err, "Error creating session")
+	}
+
+	if err := insertRefreshToken(tx, token); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+func (repo *Repository) GetSession(sessionId uuid.UUID) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := repo.db.Get(&session, `SELECT * FROM auth_sessions WHERE id = $1`, sessionId)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundError("Session not found")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving session")
+	}
+
+	return &session, nil
+}
+
+// GetActiveSessions lists the user's sessions that are neither revoked nor expired, most
+// recently used first
+func (repo *Repository) GetActiveSessions(userId uuid.UUID) ([]models.AuthSession, error) {
+	sessions := []models.AuthSession{}
+	err := repo.db.Select(&sessions, `
+		SELECT * FROM auth_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC`, userId, time.Now())
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving sessions")
+	}
+
+	return sessions, nil
+}
+
+// TouchSession records that a session was used, throttled to one write per minute like API keys
+func (repo *Repository) TouchSession(sessionId uuid.UUID) error {
+	now := time.Now()
+	_, err := repo.db.Exec(`
+		UPDATE auth_sessions SET last_used_at = $2
+		WHERE id = $1 AND last_used_at < $3`, sessionId, now, now.Add(-time.Minute))
+	if err != nil {
+		return errors.DatabaseError(err, "Error updating session activity")
+	}
+
+	return nil
+}
+
+// RotateRefreshToken exchanges a refresh token for next. Presenting a token that was already
+// rotated means it leaked, so the whole session is revoked and reused is reported instead.
+func (repo *Repository) RotateRefreshToken(tokenHash string, next *models.RefreshToken) (*models.AuthSession, bool, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return nil, false, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	var current models.RefreshToken
+	err = tx.Get(&current, `SELECT * FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`, tokenHash)
+	if err == sql.ErrNoRows {
+		return nil, false, errors.UnauthorizedError("Invalid refresh token")
+	} else if err != nil {
+		return nil, false, errors.DatabaseError(err, "Error retrieving refresh token")
+	}
+
+	var session models.AuthSession
+	err = tx.Get(&session, `SELECT * FROM auth_sessions WHERE id = $1 FOR UPDATE`, current.SessionID)
+	if err != nil {
+		return nil, false, errors.DatabaseError(err, "Error retrieving session")
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return nil, false, errors.UnauthorizedError("Session has ended")
+	}
+
+	if current.UsedAt != nil {
+		if err := revokeSession(tx, session.ID, models.RevokedReuse); err != nil {
+			return nil, false, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, false, errors.DatabaseError(err, "Error committing transaction")
+		}
+		return &session, true, nil
+	}
+
+	if now.After(current.ExpiresAt) {
+		return nil, false, errors.UnauthorizedError("Refresh token expired")
+	}
+
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET used_at = $2 WHERE id = $1`, current.ID, now); err != nil {
+		return nil, false, errors.DatabaseError(err, "Error rotating refresh token")
+	}
+
+	next.SessionID = session.ID
+	if err := insertRefreshToken(tx, next); err != nil {
+		return nil, false, err
+	}
+
+	session.LastUsedAt = now
+	session.ExpiresAt = next.ExpiresAt
+	_, err = tx.Exec(`UPDATE auth_sessions SET last_used_at = $2, expires_at = $3 WHERE id = $1`,
+		session.ID, session.LastUsedAt, session.ExpiresAt)
+	if err != nil {
+		return nil, false, errors.DatabaseError(err, "Error updating session")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return &session, false, nil
+}
+
+func (repo *Repository) RevokeSession(sessionId uuid.UUID, reason string) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if err := revokeSession(tx, sessionId, reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revokes every active session of a user except keep, returning the revoked IDs
+func (repo *Repository) RevokeUserSessions(userId, keep uuid.UUID, reason string) ([]uuid.UUID, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	revoked, err := revokeUserSessions(tx, userId, keep, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return revoked, nil
+}
+
+// CreateUserToken stores a new token, replacing any unused token of the same purpose so that
+// only the most recent email link works
+func (repo *Repository) CreateUserToken(token *models.UserToken) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		token.UserID, token.Purpose)
+	if err != nil {
+		return errors.DatabaseError(err, "Error removing previous tokens")
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO user_tokens (id, user_id, purpose, token_hash, created_at, expires_at)
+		VALUES (:id, :user_id, :purpose, :token_hash, :created_at, :expires_at)`, token)
+	if err != nil {
+		return errors.DatabaseError(err, "Error storing token")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+// ResetPassword consumes a password reset token, sets the new password hash and revokes every
+// active session of the user. The revoked session IDs are returned.
+func (repo *Repository) ResetPassword(tokenHash, passwordHash string) ([]uuid.UUID, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	token, err := consumeUserToken(tx, tokenHash, models.TokenPasswordReset)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE users SET password = $2, updated_at = $3 WHERE id = $1`,
+		token.UserID, passwordHash, time.Now())
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error updating password")
+	}
+
+	revoked, err := revokeUserSessions(tx, token.UserID, uuid.Nil, models.RevokedReset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return revoked, nil
+}
+
+// VerifyEmail consumes an email verification token and marks the user's email as verified
+func (repo *Repository) VerifyEmail(tokenHash string) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	token, err := consumeUserToken(tx, tokenHash, models.TokenEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users SET email_verified_at = $2, updated_at = $2
+		WHERE id = $1 AND email_verified_at IS NULL`, token.UserID, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error verifying email")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+func (repo *Repository) GetAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := repo.db.Get(&key, `SELECT * FROM api_keys WHERE key_hash = $1`, keyHash)
+	if err == sql.ErrNoRows {
+		return nil, errors.UnauthorizedError("Invalid API key")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving API key")
+	}
+
+	return &key, nil
+}
+
+// TouchAPIKey records that a key was used. Writes are throttled to one per minute per key so
+// busy clients do not turn every read into a write.
+func (repo *Repository) TouchAPIKey(keyId uuid.UUID) error {
+	now := time.Now()
+	_, err := repo.db.Exec(`
+		UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)`, keyId, now, now.Add(-time.Minute))
+	if err != nil {
+		return errors.DatabaseError(err, "Error updating API key usage")
+	}
+
+	return nil
+}
+
+// GetTwoFactor returns the user's TOTP enrollment, or nil when there is none
+func (repo *Repository) GetTwoFactor(userId uuid.UUID) (*models.TwoFactor, error) {
+	var twoFactor models.TwoFactor
+	err := repo.db.Get(&twoFactor, `SELECT * FROM user_two_factor WHERE user_id = $1`, userId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving two-factor settings")
+	}
+
+	return &twoFactor, nil
+}
+
+// SaveTwoFactorSecret starts or restarts a pending enrollment. An enabled enrollment is
+// never overwritten.
+func (repo *Repository) SaveTwoFactorSecret(userId uuid.UUID, secret string) error {
+	now := time.Now()
+	result, err := repo.db.Exec(`
+		INSERT INTO user_two_factor (user_id, secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = EXCLUDED.updated_at
+		WHERE user_two_factor.enabled_at IS NULL`, userId, secret, now)
+	if err != nil {
+		return errors.DatabaseError(err, "Error saving two-factor secret")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.ConflictError("Two-factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// EnableTwoFactor activates a pending enrollment with the step of the confirming code and
+// stores its recovery codes
+func (repo *Repository) EnableTwoFactor(userId uuid.UUID, step int64, codeHashes []string) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE user_two_factor SET enabled_at = $2, last_used_step = $3, updated_at = $2
+		WHERE user_id = $1 AND enabled_at IS NULL`, userId, now, step)
+	if err != nil {
+		return errors.DatabaseError(err, "Error enabling two-factor authentication")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.ConflictError("Two-factor authentication is already enabled")
+	}
+
+	if err := replaceRecoveryCodes(tx, userId, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+func (repo *Repository) ReplaceRecoveryCodes(userId uuid.UUID, codeHashes []string) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userId, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+// UseTwoFactorStep records a TOTP time step as used. It reports false if that step or a later
+// one was already used, which makes every code single-use.
+func (repo *Repository) UseTwoFactorStep(userId uuid.UUID, step int64) (bool, error) {
+	result, err := repo.db.Exec(`
+		UPDATE user_two_factor SET last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND last_used_step < $2`, userId, step, time.Now())
+	if err != nil {
+		return false, errors.DatabaseError(err, "Error recording two-factor code")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used, reporting whether one matched
+func (repo *Repository) UseRecoveryCode(userId uuid.UUID, codeHash string) (bool, error) {
+	result, err := repo.db.Exec(`
+		UPDATE user_recovery_codes SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userId, codeHash, time.Now())
+	if err != nil {
+		return false, errors.DatabaseError(err, "Error using recovery code")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+func (repo *Repository) DisableTwoFactor(userId uuid.UUID) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return errors.DatabaseError(err, "Error removing recovery codes")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM user_two_factor WHERE user_id = $1`, userId); err != nil {
+		return errors.DatabaseError(err, "Error disabling two-factor authentication")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+// ConsumeUserToken marks a token as used and returns it, for purposes that need no other change
+// in the same transaction
+func (repo *Repository) ConsumeUserToken(tokenHash, purpose string) (*models.UserToken, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	token, err := consumeUserToken(tx, tokenHash, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return token, nil
+}
+
+// GetUserByIdentity returns the user linked to an external identity, or nil when none is
+func (repo *Repository) GetUserByIdentity(issuer, subject string) (*models.User, error) {
+	var user models.User
+	query := `SELECT u.id, u.username, u.email, u.password, u.role, u.avatar, u.avatar_key, u.email_verified_at, u.suspended_at, u.suspended_reason, u.created_at, u.updated_at
+			  FROM users u
+			  INNER JOIN user_identities i ON i.user_id = u.id
+			  WHERE i.issuer = $1 AND i.subject = $2`
+
+	err := repo.db.Get(&user, query, issuer, subject)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving user by identity")
+	}
+
+	return &user, nil
+}
+
+// SaveIdentity links an external identity to a user, or refreshes an existing link
+func (repo *Repository) SaveIdentity(identity *models.UserIdentity) error {
+	_, err := repo.db.NamedExec(`
+		INSERT INTO user_identities (id, user_id, issuer, subject, email, created_at, last_login_at)
+		VALUES (:id, :user_id, :issuer, :subject, :email, :created_at, :last_login_at)
+		ON CONFLICT (issuer, subject) DO UPDATE
+		SET email = EXCLUDED.email, last_login_at = EXCLUDED.last_login_at`, identity)
+	if err != nil {
+		return errors.DatabaseError(err, "Error linking identity")
+	}
+
+	return nil
+}
+
+func (repo *Repository) MarkEmailVerified(userId uuid.UUID) error {
+	_, err := repo.db.Exec(`
+		UPDATE users SET email_verified_at = $2, updated_at = $2
+		WHERE id = $1 AND email_verified_at IS NULL`, userId, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error verifying email")
+	}
+
+	return nil
+}
+
+// Helper functions
+func replaceRecoveryCodes(tx *sqlx.Tx, userId uuid.UUID, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return errors.DatabaseError(err, "Error removing recovery codes")
+	}
+
+	now := time.Now()
+	for _, hash := range codeHashes {
+		_, err := tx.Exec(`
+			INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)`, uuid.New(), userId, hash, now)
+		if err != nil {
+			return errors.DatabaseError(err, "Error storing recovery codes")
+		}
+	}
+
+	return nil
+}
+
+func consumeUserToken(tx *sqlx.Tx, tokenHash, purpose string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := tx.Get(&token, `
+		SELECT * FROM user_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		FOR UPDATE`, tokenHash, purpose)
+	if err == sql.ErrNoRows {
+		return nil, errors.ValidationError("Invalid or expired token")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving token")
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil || now.After(token.ExpiresAt) {
+		return nil, errors.ValidationError("Invalid or expired token")
+	}
+
+	if _, err := tx.Exec(`UPDATE user_tokens SET used_at = $2 WHERE id = $1`, token.ID, now); err != nil {
+		return nil, errors.DatabaseError(err, "Error consuming token")
+	}
+
+	return &token, nil
+}
+
+func insertRefreshToken(tx *sqlx.Tx, token *models.RefreshToken) error {
+	_, err := tx.NamedExec(`
+		INSERT INTO refresh_tokens (id, session_id, token_hash, created_at, expires_at)
+		VALUES (:id, :session_id, :token_hash, :created_at, :expires_at)`, token)
+	if err != nil {
+		return errors.DatabaseError(err, "Error storing refresh token")
+	}
+
+	return nil
+}
+
+func revokeSession(tx *sqlx.Tx, sessionId uuid.UUID, reason string) error {
+	_, err := tx.Exec(`
+		UPDATE auth_sessions SET revoked_at = $2, revoked_reason = $3
+		WHERE id = $1 AND revoked_at IS NULL`, sessionId, time.Now(), reason)
+	if err != nil {
+		return errors.DatabaseError(err, "Error revoking session")
+	}
+
+	return nil
+}
+
+func revokeUserSessions(tx *sqlx.Tx, userId, keep uuid.UUID, reason string) ([]uuid.UUID, error) {
+	var revoked []uuid.UUID
+	err := tx.Select(&revoked, `
+		UPDATE auth_sessions SET revoked_at = $3, revoked_reason = $4
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+		RETURNING id`, userId, keep, time.Now(), reason)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error revoking sessions")
+	}
+
+	return revoked, nil
+}
@@ -1,23 +1,53 @@
 package auth
 
 import (
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/venntry/config"
 	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/cache"
 	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/mailer"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+
+	// How long the active/revoked state of a session is cached for AuthMiddleware
+	sessionStatusTTL = time.Minute
+
+	sessionActive  = "active"
+	sessionRevoked = "revoked"
+
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+
+	// Impersonation sessions cannot be refreshed and end after this long
+	impersonationTTL = time.Hour
+)
+
 type Service struct {
 	repo   IAuthRepository
+	cache  cache.IRedisService
+	mailer mailer.IMailer
+	oidc   *oidcClient
 	config *config.Variables
 }
 
-func NewService(repo IAuthRepository, cfg *config.Variables) IAuthService {
-	return &Service{repo: repo, config: cfg}
+func NewService(repo IAuthRepository, cache cache.IRedisService, mailer mailer.IMailer, cfg *config.Variables) IAuthService {
+	return &Service{repo: repo, cache: cache, mailer: mailer, oidc: newOIDCClient(cfg), config: cfg}
+}
+
+func sessionCacheKey(sessionId uuid.UUID) string {
+	return "session:" + sessionId.String()
 }
 
 func (service *Service) RegisterUser(username, email, password string) (*models.User, error) {
@@ -39,50 +69,253 @@ func (service *Service) RegisterUser(username, email, password string) (*models.
 		return nil, err
 	}
 
+	// The account is usable right away; a failed verification email can be resent later
+	if err := service.sendVerification(user); err != nil {
+		logger.LogError("Error sending verification email", err, logger.Field("user_id", user.Id))
+	}
+
 	return user, nil
 }
 
-func (service *Service) LoginUser(email, password string) (*models.UserResponse, error) {
+// LoginUser checks the password. Users with two-factor authentication get a challenge to
+// complete with CompleteTwoFactorLogin instead of a signed-in response.
+func (service *Service) LoginUser(email, password string, client models.ClientInfo) (*models.UserResponse, *models.TwoFactorChallenge, error) {
+	email = normalizeEmail(email)
+	if err := service.checkLoginAllowed(email, client.IP); err != nil {
+		return nil, nil, err
+	}
+
 	user, err := service.repo.GetUserByEmail(email)
 	if err != nil {
-		return nil, errors.NotFoundError("Invalid login credentials")
+		service.recordLoginFailure(email, client.IP, nil)
+		return nil, nil, errors.NotFoundError("Invalid login credentials")
 	}
 
 	// Check password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, errors.UnauthorizedError("Invalid login credentials")
+		service.recordLoginFailure(email, client.IP, user)
+		return nil, nil, errors.UnauthorizedError("Invalid login credentials")
 	}
 
-	// Generate JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.Id.String(),
-		"email":   user.Email,
-		"role":    user.Role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	})
+	if err := checkNotSuspended(user); err != nil {
+		return nil, nil, err
+	}
 
-	tokenString, err := token.SignedString([]byte(service.config.JWTSecret))
+	// Failures are only cleared once every factor has been checked
+	twoFactor, err := service.repo.GetTwoFactor(user.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		challenge, err := service.newChallenge(user.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	service.clearLoginFailures(email)
+
+	response, err := service.issueLogin(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// RefreshTokens rotates a refresh token and issues a new access token for its session
+func (service *Service) RefreshTokens(refreshToken string) (*models.TokenResponse, error) {
+	nextToken, next, err := newRefreshToken(uuid.Nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get user's inventories
-	inventories, err := service.repo.GetUserInventories(user.Id)
+	session, reused, err := service.repo.RotateRefreshToken(utils.HashToken(strings.TrimSpace(refreshToken)), next)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.UserResponse{
-		UserId:      user.Id.String(),
-		UserName:    user.Username,
-		Email:       user.Email,
-		Avatar:      user.Avatar,
-		Inventories: inventories,
-		Token:       tokenString,
+	if reused {
+		service.markSession(session.ID, sessionRevoked)
+		return nil, errors.UnauthorizedError("Refresh token reuse detected; session revoked")
+	}
+
+	user, err := service.repo.GetUserByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotSuspended(user); err != nil {
+		return nil, err
+	}
+
+	tokenString, expiresAt, err := service.generateAccessToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		Token:        tokenString,
+		RefreshToken: nextToken,
+		ExpiresAt:    expiresAt,
 	}, nil
 }
 
+// Logout revokes the session, invalidating its refresh tokens and access tokens at once
+func (service *Service) Logout(sessionId uuid.UUID) error {
+	if err := service.repo.RevokeSession(sessionId, models.RevokedLogout); err != nil {
+		return err
+	}
+
+	service.markSession(sessionId, sessionRevoked)
+	return nil
+}
+
+// ListSessions returns the devices the user is signed in on, flagging the one making the request
+func (service *Service) ListSessions(userId, currentSessionId uuid.UUID) ([]models.SessionResponse, error) {
+	sessions, err := service.repo.GetActiveSessions(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, models.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentSessionId,
+
+			Impersonated: session.ImpersonatorID != nil,
+		})
+	}
+
+	return response, nil
+}
+
+// TerminateSession signs one of the user's devices out. Sessions of other users are reported
+// as not found.
+func (service *Service) TerminateSession(userId, sessionId uuid.UUID) error {
+	session, err := service.repo.GetSession(sessionId)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userId {
+		return errors.NotFoundError("Session not found")
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := service.repo.RevokeSession(sessionId, models.RevokedTerminated); err != nil {
+		return err
+	}
+
+	service.markSession(sessionId, sessionRevoked)
+	return nil
+}
+
+// RevokeUserSessions signs a user out of every session except keep; pass uuid.Nil to revoke all
+func (service *Service) RevokeUserSessions(userId, keep uuid.UUID, reason string) error {
+	revoked, err := service.repo.RevokeUserSessions(userId, keep, reason)
+	if err != nil {
+		return err
+	}
+
+	for _, sessionId := range revoked {
+		service.markSession(sessionId, sessionRevoked)
+	}
+
+	return nil
+}
+
+// ForgotPassword emails a password reset link. It succeeds whether or not the email belongs
+// to an account, so the response cannot be used to discover registered addresses. The lookup,
+// token and delivery all happen in the background, so both cases return equally fast.
+func (service *Service) ForgotPassword(email string) error {
+	email = normalizeEmail(email)
+
+	go func() {
+		exists, err := service.repo.EmailExists(email)
+		if err != nil {
+			logger.LogError("Error looking up password reset email", err)
+			return
+		}
+		if !exists {
+			return
+		}
+
+		user, err := service.repo.GetUserByEmail(email)
+		if err != nil {
+			logger.LogError("Error loading password reset user", err)
+			return
+		}
+
+		msg, err := service.passwordResetMessage(user)
+		if err != nil {
+			logger.LogError("Error creating password reset token", err, logger.Field("user_id", user.Id))
+			return
+		}
+
+		if err := service.mailer.Send(msg); err != nil {
+			logger.LogError("Error sending password reset email", err, logger.Field("user_id", user.Id))
+		}
+	}()
+
+	return nil
+}
+
+// SendPasswordReset emails a reset link to a known user on behalf of an administrator
+func (service *Service) SendPasswordReset(userId uuid.UUID) error {
+	user, err := service.repo.GetUserByID(userId)
+	if err != nil {
+		return err
+	}
+
+	msg, err := service.passwordResetMessage(user)
+	if err != nil {
+		return err
+	}
+
+	return service.mailer.Send(msg)
+}
+
+// ResetPassword sets a new password using a reset token and signs the user out everywhere
+func (service *Service) ResetPassword(token, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.InternalError(err, "Error hashing password")
+	}
+
+	revoked, err := service.repo.ResetPassword(utils.HashToken(strings.TrimSpace(token)), string(hashedPassword))
+	if err != nil {
+		return err
+	}
+
+	for _, sessionId := range revoked {
+		service.markSession(sessionId, sessionRevoked)
+	}
+
+	return nil
+}
+
+func (service *Service) VerifyEmail(token string) error {
+	return service.repo.VerifyEmail(utils.HashToken(strings.TrimSpace(token)))
+}
+
+// ResendVerification sends a new verification link, invalidating the previous one
+func (service *Service) ResendVerification(user *models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return errors.ConflictError("Email is already verified")
+	}
+
+	return service.sendVerification(user)
+}
+
 func (service *Service) ValidateToken(tokenString string) (*jwt.Token, error) {
 	if service.config == nil || service.config.JWTSecret == "" {
 		return nil, errors.InternalError(nil, "JWT configuration missing")
@@ -121,21 +354,335 @@ func (service *Service) IsTokenExpired(tokenString string) bool {
 	return time.Now().Unix() > int64(exp)
 }
 
-func (service *Service) GetUserFromToken(tokenString string) (*models.User, error) {
+func (service *Service) GetUserFromToken(tokenString string) (*models.User, *models.SessionClaims, error) {
 	token, err := service.ValidateToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return nil, errors.New(errors.Unauthorized, "invalid token", 401)
+		return nil, nil, errors.New(errors.Unauthorized, "invalid token", 401)
 	}
 
 	userID, err := uuid.Parse(claims["user_id"].(string))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sid, _ := claims["sid"].(string)
+	sessionID, err := uuid.Parse(sid)
+	if err != nil {
+		return nil, nil, errors.UnauthorizedError("Token is not bound to a session")
+	}
+
+	session := &models.SessionClaims{SessionID: sessionID}
+	if imp, ok := claims["imp"].(string); ok {
+		impersonatorID, err := uuid.Parse(imp)
+		if err != nil {
+			return nil, nil, errors.UnauthorizedError("Invalid impersonation token")
+		}
+		session.ImpersonatorID = &impersonatorID
+	}
+
+	active, err := service.isSessionActive(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !active {
+		return nil, nil, errors.UnauthorizedError("Session has been revoked")
+	}
+
+	// Activity tracking must not fail the request
+	if err := service.repo.TouchSession(sessionID); err != nil {
+		logger.LogError("Error recording session activity", err, logger.Field("session_id", sessionID))
+	}
+
+	user, err := service.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Administrators may still act as a suspended user for support
+	if session.ImpersonatorID == nil {
+		if err := checkNotSuspended(user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return user, session, nil
+}
+
+// GetUserFromAPIKey authenticates a machine client by API key secret
+func (service *Service) GetUserFromAPIKey(secret string) (*models.User, *models.APIKey, error) {
+	key, err := service.repo.GetAPIKeyByHash(utils.HashToken(secret))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !key.IsActive() {
+		return nil, nil, errors.UnauthorizedError("API key has been revoked or has expired")
+	}
+
+	user, err := service.repo.GetUserByID(key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkNotSuspended(user); err != nil {
+		return nil, nil, err
+	}
+
+	// Usage tracking must not fail the request
+	if err := service.repo.TouchAPIKey(key.ID); err != nil {
+		logger.LogError("Error recording API key usage", err, logger.Field("api_key_id", key.ID))
+	}
+
+	return user, key, nil
+}
+
+// Impersonate opens a session as another user for a system administrator. The session is
+// marked with the administrator, cannot be refreshed, and other administrators cannot be
+// impersonated.
+func (service *Service) Impersonate(admin *models.User, userId uuid.UUID, client models.ClientInfo) (*models.ImpersonationResponse, error) {
+	if admin.Id == userId {
+		return nil, errors.ValidationError("You cannot impersonate yourself")
+	}
+
+	user, err := service.repo.GetUserByID(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "admin" {
+		return nil, errors.ForbiddenError("Administrators cannot be impersonated")
+	}
+
+	now := time.Now()
+	session := &models.AuthSession{
+		ID:             uuid.New(),
+		UserID:         user.Id,
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IP,
+		ImpersonatorID: &admin.Id,
+		CreatedAt:      now,
+		LastUsedAt:     now,
+		ExpiresAt:      now.Add(impersonationTTL),
+	}
+
+	// The refresh token is stored but never handed out
+	_, token, err := newRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	token.ExpiresAt = session.ExpiresAt
+
+	if err := service.repo.CreateSession(session, token); err != nil {
+		return nil, err
+	}
+
+	tokenString, expiresAt, err := service.generateAccessToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ImpersonationResponse{
+		SessionID: session.ID,
+		UserID:    user.Id,
+		Token:     tokenString,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Helper methods
+
+// checkNotSuspended refuses sign-ins and requests for suspended accounts
+func checkNotSuspended(user *models.User) error {
+	if user.SuspendedAt != nil {
+		return errors.ForbiddenError("This account has been suspended")
+	}
+	return nil
+}
+
+// issueLogin starts a session for an authenticated user and builds the signed-in response
+func (service *Service) issueLogin(user *models.User, client models.ClientInfo) (*models.UserResponse, error) {
+	if err := checkNotSuspended(user); err != nil {
+		return nil, err
+	}
+
+	// Every login starts a new session with its own refresh token family
+	refreshToken, session, err := service.startSession(user.Id, client)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenString, expiresAt, err := service.generateAccessToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get user's inventories
+	inventories, err := service.repo.GetUserInventories(user.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	return service.repo.GetUserByID(userID)
+	return &models.UserResponse{
+		UserId:         user.Id.String(),
+		UserName:       user.Username,
+		Email:          user.Email,
+		Avatar:         user.Avatar,
+		Inventories:    inventories,
+		Token:          tokenString,
+		TokenExpiresAt: expiresAt,
+		RefreshToken:   refreshToken,
+	}, nil
+}
+
+func (service *Service) generateAccessToken(user *models.User, session *models.AuthSession) (string, time.Time, error) {
+	expiresAt := time.Now().Add(accessTokenTTL)
+
+	claims := jwt.MapClaims{
+		"user_id": user.Id.String(),
+		"email":   user.Email,
+		"role":    user.Role,
+		"sid":     session.ID.String(),
+		"exp":     expiresAt.Unix(),
+	}
+	if session.ImpersonatorID != nil {
+		claims["imp"] = session.ImpersonatorID.String()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString([]byte(service.config.JWTSecret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+func (service *Service) startSession(userId uuid.UUID, client models.ClientInfo) (string, *models.AuthSession, error) {
+	now := time.Now()
+	session := &models.AuthSession{
+		ID:         uuid.New(),
+		UserID:     userId,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IP,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(refreshTokenTTL),
+	}
+
+	refreshToken, token, err := newRefreshToken(session.ID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := service.repo.CreateSession(session, token); err != nil {
+		return "", nil, err
+	}
+
+	return refreshToken, session, nil
+}
+
+// isSessionActive checks the cached session state, falling back to the database
+func (service *Service) isSessionActive(sessionId uuid.UUID) (bool, error) {
+	var status string
+	if err := service.cache.Get(sessionCacheKey(sessionId), &status); err == nil {
+		return status == sessionActive, nil
+	}
+
+	session, err := service.repo.GetSession(sessionId)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == errors.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	active := session.RevokedAt == nil && time.Now().Before(session.ExpiresAt)
+	if active {
+		service.markSession(sessionId, sessionActive)
+	} else {
+		service.markSession(sessionId, sessionRevoked)
+	}
+
+	return active, nil
+}
+
+func (service *Service) markSession(sessionId uuid.UUID, status string) {
+	ttl := sessionStatusTTL
+	if status == sessionRevoked {
+		// Outlive every access token that could still carry this session
+		ttl = accessTokenTTL
+	}
+	service.cache.Set(sessionCacheKey(sessionId), status, ttl)
+}
+
+func (service *Service) passwordResetMessage(user *models.User) (*mailer.Message, error) {
+	raw, err := service.issueUserToken(user.Id, models.TokenPasswordReset, passwordResetTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return passwordResetEmail(user.Email, user.Username, service.link("/reset-password", raw)), nil
+}
+
+func (service *Service) sendVerification(user *models.User) error {
+	raw, err := service.issueUserToken(user.Id, models.TokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return service.mailer.Send(verificationEmail(user.Email, user.Username, service.link("/verify-email", raw)))
+}
+
+// issueUserToken stores the hash of a new single-use token and returns the raw value for the email
+func (service *Service) issueUserToken(userId uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	raw, err := utils.GenerateToken()
+	if err != nil {
+		return "", errors.InternalError(err, "Error generating token")
+	}
+
+	now := time.Now()
+	err = service.repo.CreateUserToken(&models.UserToken{
+		ID:        uuid.New(),
+		UserID:    userId,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(raw),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// link builds a frontend URL carrying token
+func (service *Service) link(path, token string) string {
+	domain := strings.TrimRight(service.config.Domain, "/")
+	if domain == "" {
+		domain = "http://localhost:3000"
+	}
+
+	return domain + path + "?token=" + url.QueryEscape(token)
+}
+
+// newRefreshToken returns a fresh refresh token and the record storing its hash
+func newRefreshToken(sessionId uuid.UUID) (string, *models.RefreshToken, error) {
+	raw, err := utils.GenerateToken()
+	if err != nil {
+		return "", nil, errors.InternalError(err, "Error generating refresh token")
+	}
+
+	now := time.Now()
+	return raw, &models.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionId,
+		TokenHash: utils.HashToken(raw),
+		CreatedAt: now,
+		ExpiresAt: now.Add(refreshTokenTTL),
+	}, nil
 }
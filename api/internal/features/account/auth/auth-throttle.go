@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+const (
+	// Failed logins are counted over a sliding window per account and per IP
+	failureWindow = 15 * time.Minute
+
+	// After backoffThreshold failures each further attempt must wait twice as long as the last,
+	// up to maxBackoff; at lockoutThreshold the account is locked for lockoutDuration
+	backoffThreshold = 3
+	maxBackoff       = 5 * time.Minute
+	lockoutThreshold = 10
+	lockoutDuration  = 30 * time.Minute
+
+	// Failures from one IP across all accounts before it is blocked for the window
+	ipFailureLimit = 50
+)
+
+func accountFailuresKey(email string) string { return "login:failures:account:" + email }
+func ipFailuresKey(ip string) string         { return "login:failures:ip:" + ip }
+func backoffKey(email string) string         { return "login:backoff:" + email }
+func lockKey(email string) string            { return "login:lock:" + email }
+
+// checkLoginAllowed rejects attempts for locked accounts, accounts in backoff and blocked IPs
+func (service *Service) checkLoginAllowed(email, ip string) error {
+	var lockedUntil time.Time
+	if err := service.cache.Get(lockKey(email), &lockedUntil); err == nil {
+		return errors.TooManyRequestsError(fmt.Sprintf(
+			"Account is temporarily locked. Use the link sent by email or try again in %s",
+			retryAfter(lockedUntil)))
+	}
+
+	var retryAt time.Time
+	if err := service.cache.Get(backoffKey(email), &retryAt); err == nil && time.Now().Before(retryAt) {
+		return errors.TooManyRequestsError(fmt.Sprintf(
+			"Too many failed login attempts. Try again in %s", retryAfter(retryAt)))
+	}
+
+	var ipFailures int
+	if err := service.cache.Get(ipFailuresKey(ip), &ipFailures); err == nil && ipFailures >= ipFailureLimit {
+		return errors.TooManyRequestsError("Too many failed login attempts from this address. Try again later")
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt. user is nil when the email is unknown; the
+// account key is tracked anyway so responses do not reveal which emails exist.
+func (service *Service) recordLoginFailure(email, ip string, user *models.User) {
+	if _, err := service.cache.Increment(ipFailuresKey(ip), failureWindow); err != nil {
+		logger.LogError("Error recording failed login", err, logger.Field("ip", ip))
+	}
+
+	failures, err := service.cache.Increment(accountFailuresKey(email), failureWindow)
+	if err != nil {
+		logger.LogError("Error recording failed login", err, logger.Field("email", email))
+		return
+	}
+
+	switch {
+	case failures >= lockoutThreshold:
+		service.lockAccount(email, user)
+	case failures >= backoffThreshold:
+		delay := time.Duration(math.Pow(2, float64(failures-backoffThreshold))) * time.Second
+		delay = min(delay, maxBackoff)
+		service.cache.Set(backoffKey(email), time.Now().Add(delay), delay)
+	}
+}
+
+func (service *Service) lockAccount(email string, user *models.User) {
+	lockedUntil := time.Now().Add(lockoutDuration)
+	if err := service.cache.Set(lockKey(email), lockedUntil, lockoutDuration); err != nil {
+		logger.LogError("Error locking account", err, logger.Field("email", email))
+		return
+	}
+
+	service.cache.Delete(accountFailuresKey(email))
+	service.cache.Delete(backoffKey(email))
+
+	if user == nil {
+		return
+	}
+
+	logger.Info("Account locked after failed logins", logger.Field("user_id", user.Id))
+
+	raw, err := service.issueUserToken(user.Id, models.TokenAccountUnlock, lockoutDuration)
+	if err != nil {
+		logger.LogError("Error issuing unlock token", err, logger.Field("user_id", user.Id))
+		return
+	}
+
+	msg := accountLockedEmail(user.Email, user.Username, service.link("/unlock-account", raw))
+	go func() {
+		if err := service.mailer.Send(msg); err != nil {
+			logger.LogError("Error sending account locked email", err, logger.Field("user_id", user.Id))
+		}
+	}()
+}
+
+// clearLoginFailures resets the account's counters and lock
+func (service *Service) clearLoginFailures(email string) {
+	service.cache.Delete(accountFailuresKey(email))
+	service.cache.Delete(backoffKey(email))
+	service.cache.Delete(lockKey(email))
+}
+
+// UnlockAccount lifts a lockout with the token from the account locked email
+func (service *Service) UnlockAccount(token string) error {
+	userToken, err := service.repo.ConsumeUserToken(utils.HashToken(strings.TrimSpace(token)), models.TokenAccountUnlock)
+	if err != nil {
+		return err
+	}
+
+	return service.UnlockUser(userToken.UserID)
+}
+
+// UnlockUser lifts a lockout on behalf of an administrator
+func (service *Service) UnlockUser(userId uuid.UUID) error {
+	user, err := service.repo.GetUserByID(userId)
+	if err != nil {
+		return err
+	}
+
+	service.clearLoginFailures(normalizeEmail(user.Email))
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func retryAfter(t time.Time) time.Duration {
+	return time.Until(t).Round(time.Second)
+}
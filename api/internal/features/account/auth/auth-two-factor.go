@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	twoFactorIssuer = "Venntry"
+
+	// How long the second login step may take, and how many codes may be tried in it
+	challengeTTL         = 5 * time.Minute
+	maxChallengeAttempts = 5
+
+	recoveryCodeCount = 10
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// loginChallenge is the cached state of a pending two-step login
+type loginChallenge struct {
+	UserID    uuid.UUID `json:"userId"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func challengeCacheKey(tokenHash string) string {
+	return "login-challenge:" + tokenHash
+}
+
+// SetupTwoFactor starts enrollment with a fresh secret. It takes effect once EnableTwoFactor
+// confirms a code generated from it.
+func (service *Service) SetupTwoFactor(user *models.User) (*models.TwoFactorSetupResponse, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.InternalError(err, "Error generating two-factor secret")
+	}
+
+	if err := service.repo.SaveTwoFactorSecret(user.Id, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, twoFactorIssuer, user.Email),
+	}, nil
+}
+
+// EnableTwoFactor confirms a pending enrollment and returns the recovery codes, which are
+// shown only this once
+func (service *Service) EnableTwoFactor(user *models.User, code string) ([]string, error) {
+	twoFactor, err := service.repo.GetTwoFactor(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, errors.ValidationError("Two-factor setup has not been started")
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, errors.ConflictError("Two-factor authentication is already enabled")
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, errors.ValidationError("Invalid authentication code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repo.EnableTwoFactor(user.Id, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTwoFactor removes two-factor authentication; it needs both the password and a code
+func (service *Service) DisableTwoFactor(user *models.User, password, code string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.UnauthorizedError("Password is incorrect")
+	}
+
+	if err := service.verifySecondFactor(user.Id, code); err != nil {
+		return err
+	}
+
+	return service.repo.DisableTwoFactor(user.Id)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, invalidating the unused ones
+func (service *Service) RegenerateRecoveryCodes(user *models.User, code string) ([]string, error) {
+	if err := service.verifySecondFactor(user.Id, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.repo.ReplaceRecoveryCodes(user.Id, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CompleteTwoFactorLogin finishes a login started by LoginUser. Wrong codes count as failed
+// logins, so the usual backoff and lockout apply to the second step too.
+func (service *Service) CompleteTwoFactorLogin(challengeToken, code string, client models.ClientInfo) (*models.UserResponse, error) {
+	key := challengeCacheKey(utils.HashToken(strings.TrimSpace(challengeToken)))
+
+	var challenge loginChallenge
+	if err := service.cache.Get(key, &challenge); err != nil || time.Now().After(challenge.ExpiresAt) {
+		return nil, errors.UnauthorizedError("Login challenge is invalid or has expired")
+	}
+
+	user, err := service.repo.GetUserByID(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := normalizeEmail(user.Email)
+	if err := service.checkLoginAllowed(email, client.IP); err != nil {
+		return nil, err
+	}
+
+	if err := service.verifySecondFactor(challenge.UserID, code); err != nil {
+		challenge.Attempts++
+		if challenge.Attempts >= maxChallengeAttempts {
+			service.cache.Delete(key)
+		} else {
+			service.cache.Set(key, challenge, time.Until(challenge.ExpiresAt))
+		}
+		service.recordLoginFailure(email, client.IP, user)
+		return nil, err
+	}
+
+	// A challenge completes exactly one login
+	service.cache.Delete(key)
+	service.clearLoginFailures(email)
+
+	return service.issueLogin(user, client)
+}
+
+func (service *Service) newChallenge(userId uuid.UUID) (*models.TwoFactorChallenge, error) {
+	token, err := utils.GenerateToken()
+	if err != nil {
+		return nil, errors.InternalError(err, "Error generating login challenge")
+	}
+
+	challenge := loginChallenge{UserID: userId, ExpiresAt: time.Now().Add(challengeTTL)}
+	if err := service.cache.Set(challengeCacheKey(utils.HashToken(token)), challenge, challengeTTL); err != nil {
+		return nil, errors.CacheError(err, "Error storing login challenge")
+	}
+
+	return &models.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         challenge.ExpiresAt,
+	}, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery code. Each is
+// accepted only once.
+func (service *Service) verifySecondFactor(userId uuid.UUID, code string) error {
+	twoFactor, err := service.repo.GetTwoFactor(userId)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		return errors.ValidationError("Two-factor authentication is not enabled")
+	}
+
+	if step, ok := totp.Validate(twoFactor.Secret, code, time.Now()); ok {
+		fresh, err := service.repo.UseTwoFactorStep(userId, step)
+		if err != nil {
+			return err
+		}
+		if fresh {
+			return nil
+		}
+		return errors.UnauthorizedError("Authentication code has already been used")
+	}
+
+	used, err := service.repo.UseRecoveryCode(userId, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.UnauthorizedError("Invalid authentication code")
+	}
+
+	return nil
+}
+
+// newRecoveryCodes returns codes formatted as xxxxx-xxxxx and the hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		buf := make([]byte, 6)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, errors.InternalError(err, "Error generating recovery codes")
+		}
+
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = utils.HashToken(raw)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -35,7 +35,7 @@ func (ctrl Controller) ListInventories(ctx echo.Context) error {
 
 	response := make([]models.InventoryResponse, len(inventories))
 	for i, inv := range inventories {
-		response[i] = *mappers.ToInventoryResponse(&inv)
+		response[i] = *mappers.ToUserInventoryResponse(&inv)
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -55,6 +55,7 @@ func (ctrl Controller) GetInventory(ctx echo.Context) error {
 	}
 
 	response := mappers.ToInventoryResponse(&inventory)
+	response.Role, _ = ctx.Get("inventoryRole").(string)
 
 	return ctx.JSON(http.StatusOK, response)
 }
@@ -80,6 +81,7 @@ func (ctrl Controller) CreateInventory(ctx echo.Context) error {
 	}
 
 	response := mappers.ToInventoryResponse(newInventory)
+	response.Role = models.RoleOwner
 
 	return ctx.JSON(http.StatusCreated, response)
 }
@@ -116,6 +118,39 @@ func (ctrl Controller) UpdateInventory(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// PatchInventory applies a JSON merge patch to an inventory
+func (ctrl Controller) PatchInventory(ctx echo.Context) error {
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	existingInventory, err := ctrl.repo.GetInventory(inventoryId)
+	if err != nil {
+		return logger.Error(ctx, "Inventory not found", err,
+			logger.Field("inventory_id", inventoryId),
+		)
+	}
+
+	req := mappers.ToInventoryRequest(&existingInventory)
+	if _, err := utils.BindMergePatch(ctx, req); err != nil {
+		return err
+	}
+
+	mappers.SanitizeInventoryRequest(req)
+
+	updatedInventory := mappers.ToEditInventory(req, &existingInventory)
+	if err := ctrl.repo.UpdateInventory(updatedInventory); err != nil {
+		return logger.Error(ctx, "Failed to patch inventory", err,
+			logger.Field("inventory_id", inventoryId),
+		)
+	}
+
+	response := mappers.ToInventoryResponse(updatedInventory)
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
 func (ctrl Controller) DeleteInventory(ctx echo.Context) error {
 	inventoryId, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -131,3 +166,52 @@ func (ctrl Controller) DeleteInventory(ctx echo.Context) error {
 
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+// UpdateSecurity sets whether members need two-factor authentication to access the inventory
+func (ctrl Controller) UpdateSecurity(ctx echo.Context) error {
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	var req models.InventorySecurityRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	// The owner would otherwise lock themselves out
+	if req.RequireTwoFactor {
+		enabled, err := ctrl.repo.UserHasTwoFactor(user.Id)
+		if err != nil {
+			return logger.Error(ctx, "Failed to check two-factor status", err,
+				logger.Field("user_id", user.Id),
+			)
+		}
+		if !enabled {
+			return errors.ValidationError("Enable two-factor authentication on your account first")
+		}
+	}
+
+	if err := ctrl.repo.SetTwoFactorRequired(inventoryId, req.RequireTwoFactor); err != nil {
+		return logger.Error(ctx, "Failed to update inventory security", err,
+			logger.Field("inventory_id", inventoryId),
+		)
+	}
+
+	inventory, err := ctrl.repo.GetInventory(inventoryId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to retrieve inventory", err,
+			logger.Field("inventory_id", inventoryId),
+		)
+	}
+
+	response := mappers.ToInventoryResponse(&inventory)
+	response.Role, _ = ctx.Get("inventoryRole").(string)
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -7,11 +7,13 @@ import (
 )
 
 type IInventoryRepository interface {
-	ListInventories(userId uuid.UUID) ([]models.Inventory, error)
+	ListInventories(userId uuid.UUID) ([]models.UserInventory, error)
 	GetInventory(inventoryId uuid.UUID) (models.Inventory, error)
 	CreateInventory(newInventory *models.Inventory) error
 	UpdateInventory(updatedInventory *models.Inventory) error
 	DeleteInventory(inventoryId uuid.UUID) error
+	UserHasTwoFactor(userId uuid.UUID) (bool, error)
+	SetTwoFactorRequired(inventoryId uuid.UUID, required bool) error
 }
 
 type IInventoriesController interface {
@@ -19,5 +21,7 @@ type IInventoriesController interface {
 	GetInventory(ctx echo.Context) error
 	CreateInventory(ctx echo.Context) error
 	UpdateInventory(ctx echo.Context) error
+	PatchInventory(ctx echo.Context) error
 	DeleteInventory(ctx echo.Context) error
+	UpdateSecurity(ctx echo.Context) error
 }
@@ -1,6 +1,8 @@
 package inventories
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/venntry/internal/models"
@@ -15,9 +17,12 @@ func NewRepository(data *sqlx.DB) IInventoryRepository {
 	return &Repository{db: data}
 }
 
-func (repo *Repository) ListInventories(userId uuid.UUID) ([]models.Inventory, error) {
-	inventories := []models.Inventory{}
-	query := `SELECT * FROM inventories WHERE user_id = $1`
+func (repo *Repository) ListInventories(userId uuid.UUID) ([]models.UserInventory, error) {
+	inventories := []models.UserInventory{}
+	query := `SELECT i.*, m.role FROM inventories i
+			  INNER JOIN inventory_members m ON m.inventory_id = i.id
+			  WHERE m.user_id = $1
+			  ORDER BY i.created_at ASC`
 
 	err := repo.db.Select(&inventories, query, userId)
 	if err != nil {
@@ -40,13 +45,31 @@ func (repo *Repository) GetInventory(inventoryId uuid.UUID) (models.Inventory, e
 }
 
 func (repo *Repository) CreateInventory(newInventory *models.Inventory) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Create Inventory")
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO inventories (
 				id, name, user_id, created_at, updated_at
 			) VALUES (
 			 	:id, :name, :user_id, :created_at, :updated_at)`
 
-	_, err := repo.db.NamedExec(query, newInventory)
+	_, err = tx.NamedExec(query, newInventory)
+	if err != nil {
+		return errors.DatabaseError(err, "Create Inventory")
+	}
+
+	// The creator becomes the inventory owner
+	_, err = tx.Exec(`INSERT INTO inventory_members (inventory_id, user_id, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)`,
+		newInventory.Id, newInventory.UserId, models.RoleOwner, newInventory.CreatedAt, newInventory.UpdatedAt)
 	if err != nil {
+		return errors.DatabaseError(err, "Create Inventory Owner")
+	}
+
+	if err = tx.Commit(); err != nil {
 		return errors.DatabaseError(err, "Create Inventory")
 	}
 
@@ -77,3 +100,26 @@ func (repo *Repository) DeleteInventory(inventoryId uuid.UUID) error {
 
 	return nil
 }
+
+func (repo *Repository) UserHasTwoFactor(userId uuid.UUID) (bool, error) {
+	var enabled bool
+	query := `SELECT EXISTS(SELECT 1 FROM user_two_factor WHERE user_id = $1 AND enabled_at IS NOT NULL)`
+
+	err := repo.db.Get(&enabled, query, userId)
+	if err != nil {
+		return false, errors.DatabaseError(err, "Check Two-Factor")
+	}
+
+	return enabled, nil
+}
+
+func (repo *Repository) SetTwoFactorRequired(inventoryId uuid.UUID, required bool) error {
+	query := `UPDATE inventories SET require_two_factor = $2, updated_at = $3 WHERE id = $1`
+
+	_, err := repo.db.Exec(query, inventoryId, required, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Edit Inventory Security")
+	}
+
+	return nil
+}
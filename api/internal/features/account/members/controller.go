@@ -0,0 +1,220 @@
+package members
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/mappers"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type Controller struct {
+	service IMemberService
+}
+
+func NewController(service IMemberService) *Controller {
+	return &Controller{service: service}
+}
+
+func (ctrl Controller) ListMembers(ctx echo.Context) error {
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	members, err := ctrl.service.ListMembers(inventoryId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch members", err,
+			logger.Field("inventory_id", inventoryId),
+		)
+	}
+
+	response := make([]models.MemberResponse, len(members))
+	for i, member := range members {
+		response[i] = *mappers.ToMemberResponse(&member)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl Controller) UpdateMemberRole(ctx echo.Context) error {
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	userId, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	var req models.MemberRoleRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	actorRole, _ := ctx.Get("inventoryRole").(string)
+	member, err := ctrl.service.ChangeMemberRole(inventoryId, actorRole, userId, req.Role)
+	if err != nil {
+		return logger.Error(ctx, "Failed to update member role", err,
+			logger.Field("inventory_id", inventoryId),
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, mappers.ToMemberResponse(member))
+}
+
+func (ctrl Controller) RemoveMember(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	userId, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		return errors.ValidationError("Invalid user ID")
+	}
+
+	actorRole, _ := ctx.Get("inventoryRole").(string)
+	if err := ctrl.service.RemoveMember(inventoryId, user.Id, actorRole, userId); err != nil {
+		return logger.Error(ctx, "Failed to remove member", err,
+			logger.Field("inventory_id", inventoryId),
+			logger.Field("user_id", userId),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl Controller) ListInvitations(ctx echo.Context) error {
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	invitations, err := ctrl.service.ListInvitations(inventoryId)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch invitations", err,
+			logger.Field("inventory_id", inventoryId),
+		)
+	}
+
+	response := make([]models.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		response[i] = *mappers.ToInvitationResponse(&invitation)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ctrl Controller) InviteMember(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	var req models.InvitationRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	actorRole, _ := ctx.Get("inventoryRole").(string)
+	invitation, err := ctrl.service.InviteMember(inventoryId, user, actorRole, &req)
+	if err != nil {
+		return logger.Error(ctx, "Failed to invite member", err,
+			logger.Field("inventory_id", inventoryId),
+			logger.Field("email", req.Email),
+		)
+	}
+
+	return ctx.JSON(http.StatusCreated, mappers.ToInvitationResponse(invitation))
+}
+
+func (ctrl Controller) RevokeInvitation(ctx echo.Context) error {
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	invitationId, err := uuid.Parse(ctx.Param("invitationId"))
+	if err != nil {
+		return errors.ValidationError("Invalid invitation ID")
+	}
+
+	if err := ctrl.service.RevokeInvitation(inventoryId, invitationId); err != nil {
+		return logger.Error(ctx, "Failed to revoke invitation", err,
+			logger.Field("inventory_id", inventoryId),
+			logger.Field("invitation_id", invitationId),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl Controller) AcceptInvitation(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	var req models.InvitationTokenRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	member, err := ctrl.service.AcceptInvitation(inventoryId, user, req.Token)
+	if err != nil {
+		return logger.Error(ctx, "Failed to accept invitation", err,
+			logger.Field("inventory_id", inventoryId),
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, mappers.ToMemberResponse(member))
+}
+
+func (ctrl Controller) DeclineInvitation(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	inventoryId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	var req models.InvitationTokenRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	if err := ctrl.service.DeclineInvitation(inventoryId, user, req.Token); err != nil {
+		return logger.Error(ctx, "Failed to decline invitation", err,
+			logger.Field("inventory_id", inventoryId),
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,23 @@
+package members
+
+import (
+	"fmt"
+
+	"github.com/venntry/pkg/mailer"
+)
+
+func invitationEmail(to, inviter, inventory, role, link string) *mailer.Message {
+	return &mailer.Message{
+		To:      to,
+		Subject: fmt.Sprintf("%s invited you to %s on Venntry", inviter, inventory),
+		Body: fmt.Sprintf(`Hi,
+
+%s invited you to join the inventory %s on Venntry as %s.
+Sign in with this email address and open the link below to accept. It expires in %s.
+
+%s
+
+If you were not expecting this invitation, you can ignore this email.
+`, inviter, inventory, role, invitationTTL, link),
+	}
+}
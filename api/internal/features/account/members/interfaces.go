@@ -0,0 +1,47 @@
+package members
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type IMemberRepository interface {
+	ListMembers(inventoryId uuid.UUID) ([]models.MemberDetails, error)
+	GetMember(inventoryId, userId uuid.UUID) (models.MemberDetails, error)
+	UpdateMemberRole(inventoryId, userId uuid.UUID, role string) error
+	RemoveMember(inventoryId, userId uuid.UUID) error
+	IsMemberEmail(inventoryId uuid.UUID, email string) (bool, error)
+	GetInventoryName(inventoryId uuid.UUID) (string, error)
+
+	ListInvitations(inventoryId uuid.UUID) ([]models.Invitation, error)
+	GetInvitation(invitationId uuid.UUID) (models.Invitation, error)
+	GetPendingInvitation(inventoryId uuid.UUID, tokenHash string) (models.Invitation, error)
+	CreateInvitation(invitation *models.Invitation) error
+	AcceptInvitation(invitation *models.Invitation, userId uuid.UUID) error
+	UpdateInvitationStatus(invitationId uuid.UUID, status string) error
+}
+
+type IMemberService interface {
+	ListMembers(inventoryId uuid.UUID) ([]models.MemberDetails, error)
+	ChangeMemberRole(inventoryId uuid.UUID, actorRole string, userId uuid.UUID, role string) (*models.MemberDetails, error)
+	RemoveMember(inventoryId, actorId uuid.UUID, actorRole string, userId uuid.UUID) error
+
+	ListInvitations(inventoryId uuid.UUID) ([]models.Invitation, error)
+	InviteMember(inventoryId uuid.UUID, actor *models.User, actorRole string, req *models.InvitationRequest) (*models.Invitation, error)
+	RevokeInvitation(inventoryId, invitationId uuid.UUID) error
+	AcceptInvitation(inventoryId uuid.UUID, user *models.User, token string) (*models.MemberDetails, error)
+	DeclineInvitation(inventoryId uuid.UUID, user *models.User, token string) error
+}
+
+type IMembersController interface {
+	ListMembers(ctx echo.Context) error
+	UpdateMemberRole(ctx echo.Context) error
+	RemoveMember(ctx echo.Context) error
+
+	ListInvitations(ctx echo.Context) error
+	InviteMember(ctx echo.Context) error
+	RevokeInvitation(ctx echo.Context) error
+	AcceptInvitation(ctx echo.Context) error
+	DeclineInvitation(ctx echo.Context) error
+}
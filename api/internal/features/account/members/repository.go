@@ -0,0 +1,232 @@
+package members
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(data *sqlx.DB) IMemberRepository {
+	return &Repository{db: data}
+}
+
+const memberColumns = `m.inventory_id, m.user_id, m.role, m.created_at, m.updated_at,
+			  u.username, u.email, u.avatar`
+
+func (repo *Repository) ListMembers(inventoryId uuid.UUID) ([]models.MemberDetails, error) {
+	members := []models.MemberDetails{}
+	query := `SELECT ` + memberColumns + `
+			  FROM inventory_members m
+			  INNER JOIN users u ON u.id = m.user_id
+			  WHERE m.inventory_id = $1
+			  ORDER BY m.created_at ASC`
+
+	err := repo.db.Select(&members, query, inventoryId)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "List Members")
+	}
+
+	return members, nil
+}
+
+func (repo *Repository) GetMember(inventoryId, userId uuid.UUID) (models.MemberDetails, error) {
+	var member models.MemberDetails
+	query := `SELECT ` + memberColumns + `
+			  FROM inventory_members m
+			  INNER JOIN users u ON u.id = m.user_id
+			  WHERE m.inventory_id = $1 AND m.user_id = $2`
+
+	err := repo.db.Get(&member, query, inventoryId, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return member, errors.NotFoundError("Member not found")
+		}
+		return member, errors.DatabaseError(err, "Get Member")
+	}
+
+	return member, nil
+}
+
+func (repo *Repository) UpdateMemberRole(inventoryId, userId uuid.UUID, role string) error {
+	query := `UPDATE inventory_members SET role = $1, updated_at = $2
+			  WHERE inventory_id = $3 AND user_id = $4`
+
+	_, err := repo.db.Exec(query, role, time.Now(), inventoryId, userId)
+	if err != nil {
+		return errors.DatabaseError(err, "Update Member Role")
+	}
+
+	return nil
+}
+
+func (repo *Repository) RemoveMember(inventoryId, userId uuid.UUID) error {
+	query := `DELETE FROM inventory_members WHERE inventory_id = $1 AND user_id = $2`
+
+	_, err := repo.db.Exec(query, inventoryId, userId)
+	if err != nil {
+		return errors.DatabaseError(err, "Remove Member")
+	}
+
+	return nil
+}
+
+func (repo *Repository) IsMemberEmail(inventoryId uuid.UUID, email string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(
+				SELECT 1 FROM inventory_members m
+				INNER JOIN users u ON u.id = m.user_id
+				WHERE m.inventory_id = $1 AND u.email = $2
+			  )`
+
+	err := repo.db.Get(&exists, query, inventoryId, email)
+	if err != nil {
+		return false, errors.DatabaseError(err, "Check Member Email")
+	}
+
+	return exists, nil
+}
+
+func (repo *Repository) GetInventoryName(inventoryId uuid.UUID) (string, error) {
+	var name string
+	err := repo.db.Get(&name, `SELECT name FROM inventories WHERE id = $1`, inventoryId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.NotFoundError("Inventory not found")
+		}
+		return "", errors.DatabaseError(err, "Get Inventory Name")
+	}
+
+	return name, nil
+}
+
+func (repo *Repository) ListInvitations(inventoryId uuid.UUID) ([]models.Invitation, error) {
+	invitations := []models.Invitation{}
+	query := `SELECT * FROM inventory_invitations
+			  WHERE inventory_id = $1 AND status = $2 AND expires_at > $3
+			  ORDER BY created_at DESC`
+
+	err := repo.db.Select(&invitations, query, inventoryId, models.InvitationPending, time.Now())
+	if err != nil {
+		return nil, errors.DatabaseError(err, "List Invitations")
+	}
+
+	return invitations, nil
+}
+
+func (repo *Repository) GetInvitation(invitationId uuid.UUID) (models.Invitation, error) {
+	var invitation models.Invitation
+	query := `SELECT * FROM inventory_invitations WHERE id = $1`
+
+	err := repo.db.Get(&invitation, query, invitationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return invitation, errors.NotFoundError("Invitation not found")
+		}
+		return invitation, errors.DatabaseError(err, "Get Invitation")
+	}
+
+	return invitation, nil
+}
+
+func (repo *Repository) GetPendingInvitation(inventoryId uuid.UUID, tokenHash string) (models.Invitation, error) {
+	var invitation models.Invitation
+	query := `SELECT * FROM inventory_invitations
+			  WHERE inventory_id = $1 AND token_hash = $2 AND status = $3 AND expires_at > $4`
+
+	err := repo.db.Get(&invitation, query, inventoryId, tokenHash, models.InvitationPending, time.Now())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return invitation, errors.NotFoundError("Invitation not found or expired")
+		}
+		return invitation, errors.DatabaseError(err, "Get Invitation")
+	}
+
+	return invitation, nil
+}
+
+func (repo *Repository) CreateInvitation(invitation *models.Invitation) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Create Invitation")
+	}
+	defer tx.Rollback()
+
+	// Expired invitations no longer block a fresh invite for the same email
+	_, err = tx.Exec(`UPDATE inventory_invitations SET status = $1, updated_at = $2
+			WHERE inventory_id = $3 AND email = $4 AND status = $5 AND expires_at <= $2`,
+		models.InvitationRevoked, time.Now(), invitation.InventoryId, invitation.Email, models.InvitationPending)
+	if err != nil {
+		return errors.DatabaseError(err, "Expire Invitations")
+	}
+
+	query := `INSERT INTO inventory_invitations (
+				id, inventory_id, email, role, token_hash, status,
+				invited_by, expires_at, created_at, updated_at
+			) VALUES (
+				:id, :inventory_id, :email, :role, :token_hash, :status,
+				:invited_by, :expires_at, :created_at, :updated_at)`
+
+	_, err = tx.NamedExec(query, invitation)
+	if err != nil {
+		return errors.DatabaseError(err, "Create Invitation")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Create Invitation")
+	}
+
+	return nil
+}
+
+// AcceptInvitation claims the invitation before adding the member, so one that was revoked,
+// declined or expired since it was read never turns into a membership
+func (repo *Repository) AcceptInvitation(invitation *models.Invitation, userId uuid.UUID) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Accept Invitation")
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`UPDATE inventory_invitations SET status = $1, updated_at = $2
+			WHERE id = $3 AND status = $4 AND expires_at > $2`,
+		models.InvitationAccepted, now, invitation.ID, models.InvitationPending)
+	if err != nil {
+		return errors.DatabaseError(err, "Accept Invitation")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.NotFoundError("Invitation not found or expired")
+	}
+
+	_, err = tx.Exec(`INSERT INTO inventory_members (inventory_id, user_id, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $4)`,
+		invitation.InventoryId, userId, invitation.Role, now)
+	if err != nil {
+		return errors.DatabaseError(err, "Accept Invitation")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Accept Invitation")
+	}
+
+	return nil
+}
+
+func (repo *Repository) UpdateInvitationStatus(invitationId uuid.UUID, status string) error {
+	query := `UPDATE inventory_invitations SET status = $1, updated_at = $2 WHERE id = $3`
+
+	_, err := repo.db.Exec(query, status, time.Now(), invitationId)
+	if err != nil {
+		return errors.DatabaseError(err, "Update Invitation")
+	}
+
+	return nil
+}
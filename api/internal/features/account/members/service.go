@@ -0,0 +1,223 @@
+package members
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/config"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/mailer"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+type Service struct {
+	repo   IMemberRepository
+	mailer mailer.IMailer
+	config *config.Variables
+}
+
+func NewService(repo IMemberRepository, mailer mailer.IMailer, cfg *config.Variables) IMemberService {
+	return &Service{repo: repo, mailer: mailer, config: cfg}
+}
+
+func (service *Service) ListMembers(inventoryId uuid.UUID) ([]models.MemberDetails, error) {
+	return service.repo.ListMembers(inventoryId)
+}
+
+func (service *Service) ChangeMemberRole(inventoryId uuid.UUID, actorRole string, userId uuid.UUID, role string) (*models.MemberDetails, error) {
+	member, err := service.repo.GetMember(inventoryId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(actorRole, member.Role) || !canAssign(actorRole, role) {
+		return nil, errors.ForbiddenError("Insufficient permissions to change this member's role")
+	}
+
+	if err := service.repo.UpdateMemberRole(inventoryId, userId, role); err != nil {
+		return nil, err
+	}
+
+	member.Role = role
+	member.UpdatedAt = time.Now()
+	return &member, nil
+}
+
+func (service *Service) RemoveMember(inventoryId, actorId uuid.UUID, actorRole string, userId uuid.UUID) error {
+	member, err := service.repo.GetMember(inventoryId, userId)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == models.RoleOwner {
+		return errors.ForbiddenError("The inventory owner cannot be removed")
+	}
+
+	// Members may always leave an inventory themselves
+	if actorId != userId && !canManage(actorRole, member.Role) {
+		return errors.ForbiddenError("Insufficient permissions to remove this member")
+	}
+
+	return service.repo.RemoveMember(inventoryId, userId)
+}
+
+func (service *Service) ListInvitations(inventoryId uuid.UUID) ([]models.Invitation, error) {
+	return service.repo.ListInvitations(inventoryId)
+}
+
+// InviteMember records an invitation and emails its link to the invitee. The token only ever
+// travels by email, so just the owner of the address can respond to it.
+func (service *Service) InviteMember(inventoryId uuid.UUID, actor *models.User, actorRole string, req *models.InvitationRequest) (*models.Invitation, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if !canAssign(actorRole, req.Role) {
+		return nil, errors.ForbiddenError("Insufficient permissions to invite with this role")
+	}
+
+	isMember, err := service.repo.IsMemberEmail(inventoryId, email)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, errors.ConflictError("User is already a member of this inventory")
+	}
+
+	inventoryName, err := service.repo.GetInventoryName(inventoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateToken()
+	if err != nil {
+		return nil, errors.InternalError(err, "Error generating invitation token")
+	}
+
+	now := time.Now()
+	invitation := &models.Invitation{
+		ID:          uuid.New(),
+		InventoryId: inventoryId,
+		Email:       email,
+		Role:        req.Role,
+		TokenHash:   utils.HashToken(token),
+		Status:      models.InvitationPending,
+		InvitedBy:   actor.Id,
+		ExpiresAt:   now.Add(invitationTTL),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := service.repo.CreateInvitation(invitation); err != nil {
+		return nil, err
+	}
+
+	msg := invitationEmail(email, actor.Username, inventoryName, req.Role, service.invitationLink(inventoryId, token))
+	if err := service.mailer.Send(msg); err != nil {
+		// Nobody could ever answer an invitation whose email was lost
+		if err := service.repo.UpdateInvitationStatus(invitation.ID, models.InvitationRevoked); err != nil {
+			logger.LogError("Error revoking undelivered invitation", err, logger.Field("invitation_id", invitation.ID))
+		}
+		return nil, errors.InternalError(err, "Error sending invitation email")
+	}
+
+	return invitation, nil
+}
+
+func (service *Service) RevokeInvitation(inventoryId, invitationId uuid.UUID) error {
+	invitation, err := service.repo.GetInvitation(invitationId)
+	if err != nil {
+		return err
+	}
+
+	if invitation.InventoryId != inventoryId {
+		return errors.NotFoundError("Invitation not found")
+	}
+	if invitation.Status != models.InvitationPending {
+		return errors.ConflictError("Invitation is no longer pending")
+	}
+
+	return service.repo.UpdateInvitationStatus(invitationId, models.InvitationRevoked)
+}
+
+func (service *Service) AcceptInvitation(inventoryId uuid.UUID, user *models.User, token string) (*models.MemberDetails, error) {
+	// Anyone can sign up with the invited address; only its verified owner may join
+	if user.EmailVerifiedAt == nil {
+		return nil, errors.ForbiddenError("Verify your email address before accepting invitations")
+	}
+
+	invitation, err := service.findInvitationFor(inventoryId, user, token)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := service.repo.IsMemberEmail(inventoryId, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, errors.ConflictError("You are already a member of this inventory")
+	}
+
+	if err := service.repo.AcceptInvitation(invitation, user.Id); err != nil {
+		return nil, err
+	}
+
+	member, err := service.repo.GetMember(inventoryId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (service *Service) DeclineInvitation(inventoryId uuid.UUID, user *models.User, token string) error {
+	invitation, err := service.findInvitationFor(inventoryId, user, token)
+	if err != nil {
+		return err
+	}
+
+	return service.repo.UpdateInvitationStatus(invitation.ID, models.InvitationDeclined)
+}
+
+// Helper methods
+func (service *Service) findInvitationFor(inventoryId uuid.UUID, user *models.User, token string) (*models.Invitation, error) {
+	invitation, err := service.repo.GetPendingInvitation(inventoryId, utils.HashToken(strings.TrimSpace(token)))
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, errors.ForbiddenError("This invitation was issued to a different email")
+	}
+
+	return &invitation, nil
+}
+
+// invitationLink points the invitee at the page that accepts or declines the invitation
+func (service *Service) invitationLink(inventoryId uuid.UUID, token string) string {
+	domain := strings.TrimRight(service.config.Domain, "/")
+	if domain == "" {
+		domain = "http://localhost:3000"
+	}
+
+	return domain + "/inventories/" + inventoryId.String() + "/invitation?token=" + url.QueryEscape(token)
+}
+
+// canManage reports whether an actor may change or remove a member holding role.
+func canManage(actorRole, role string) bool {
+	return access.Outranks(actorRole, role)
+}
+
+// canAssign reports whether an actor may grant role. Only owners can create admins.
+func canAssign(actorRole, role string) bool {
+	if role == models.RoleOwner {
+		return false
+	}
+	return actorRole == models.RoleOwner || access.Outranks(actorRole, role)
+}
@@ -0,0 +1,130 @@
+package profile
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type Controller struct {
+	service IProfileService
+}
+
+func NewController(service IProfileService) *Controller {
+	return &Controller{service: service}
+}
+
+func (ctrl *Controller) UpdateProfile(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	var req models.UpdateProfileRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	updated, err := ctrl.service.UpdateProfile(user, &req)
+	if err != nil {
+		return logger.Error(ctx, "Failed to update profile", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, updated)
+}
+
+func (ctrl *Controller) ChangePassword(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+	sessionId, _ := ctx.Get("sessionId").(uuid.UUID)
+
+	var req models.ChangePasswordRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	if err := ctrl.service.ChangePassword(user, sessionId, &req); err != nil {
+		return logger.Error(ctx, "Failed to change password", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *Controller) UploadAvatar(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	fileHeader, err := ctx.FormFile("avatar")
+	if err != nil {
+		return errors.ValidationError("An avatar file is required in the 'avatar' form field")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return logger.Error(ctx, "Failed to read uploaded file", err)
+	}
+	defer file.Close()
+
+	updated, err := ctrl.service.UploadAvatar(ctx.Request().Context(), user, file, fileHeader.Size)
+	if err != nil {
+		return logger.Error(ctx, "Failed to upload avatar", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, updated)
+}
+
+func (ctrl *Controller) RemoveAvatar(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	if err := ctrl.service.RemoveAvatar(ctx.Request().Context(), user); err != nil {
+		return logger.Error(ctx, "Failed to remove avatar", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *Controller) DeleteAccount(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return logger.Error(ctx, "User not found in context",
+			errors.UnauthorizedError("Unauthorized user"))
+	}
+
+	var req models.DeleteAccountRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	if err := ctrl.service.DeleteAccount(user, req.Password); err != nil {
+		return logger.Error(ctx, "Failed to delete account", err,
+			logger.Field("user_id", user.Id),
+		)
+	}
+
+	logger.Info("User account deleted", logger.Field("user_id", user.Id))
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,33 @@
+package profile
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type IProfileRepository interface {
+	UpdateProfile(userId uuid.UUID, username, email string, emailChanged bool) (*models.User, error)
+	UpdatePassword(userId uuid.UUID, passwordHash string) error
+	UpdateAvatar(userId uuid.UUID, url, fileKey *string) error
+	DeleteUser(userId uuid.UUID) ([]string, error)
+}
+
+type IProfileService interface {
+	UpdateProfile(user *models.User, req *models.UpdateProfileRequest) (*models.User, error)
+	ChangePassword(user *models.User, sessionId uuid.UUID, req *models.ChangePasswordRequest) error
+	UploadAvatar(ctx context.Context, user *models.User, file io.Reader, size int64) (*models.User, error)
+	RemoveAvatar(ctx context.Context, user *models.User) error
+	DeleteAccount(user *models.User, password string) error
+}
+
+type IProfileController interface {
+	UpdateProfile(ctx echo.Context) error
+	ChangePassword(ctx echo.Context) error
+	UploadAvatar(ctx echo.Context) error
+	RemoveAvatar(ctx echo.Context) error
+	DeleteAccount(ctx echo.Context) error
+}
@@ -0,0 +1,108 @@
+package profile
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type Repository struct {
+	db *sqlx.DB
+}
+
+func NewRepository(db *sqlx.DB) IProfileRepository {
+	return &Repository{db: db}
+}
+
+// UpdateProfile stores a new username and email. A changed email is no longer verified.
+func (repo *Repository) UpdateProfile(userId uuid.UUID, username, email string, emailChanged bool) (*models.User, error) {
+	var user models.User
+	err := repo.db.Get(&user, `
+		UPDATE users
+		SET username = $2, email = $3, updated_at = $4,
+			email_verified_at = CASE WHEN $5 THEN NULL ELSE email_verified_at END
+		WHERE id = $1
+		RETURNING id, username, email, password, role, avatar, avatar_key, email_verified_at, suspended_at, suspended_reason, created_at, updated_at`,
+		userId, username, email, time.Now(), emailChanged)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error updating profile")
+	}
+
+	return &user, nil
+}
+
+func (repo *Repository) UpdatePassword(userId uuid.UUID, passwordHash string) error {
+	_, err := repo.db.Exec(`UPDATE users SET password = $2, updated_at = $3 WHERE id = $1`,
+		userId, passwordHash, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error updating password")
+	}
+
+	return nil
+}
+
+func (repo *Repository) UpdateAvatar(userId uuid.UUID, url, fileKey *string) error {
+	_, err := repo.db.Exec(`UPDATE users SET avatar = $2, avatar_key = $3, updated_at = $4 WHERE id = $1`,
+		userId, url, fileKey, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error updating avatar")
+	}
+
+	return nil
+}
+
+// DeleteUser removes the user. Owned inventories and everything in them go with it through
+// ON DELETE CASCADE. The storage keys the server issued for their product images and the avatar
+// are returned so the objects can be removed from the bucket; keys still referenced outside the
+// user's inventories are left out.
+func (repo *Repository) DeleteUser(userId uuid.UUID) ([]string, error) {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	var fileKeys []string
+	err = tx.Select(&fileKeys, `
+		WITH owned AS (
+			SELECT pi.file_key, pi.variants
+			FROM product_images pi
+			INNER JOIN products p ON p.id = pi.product_id
+			INNER JOIN inventories i ON i.id = p.inventory_id
+			WHERE i.user_id = $1 AND pi.managed
+		), keys AS (
+			SELECT file_key AS key FROM owned
+			UNION
+			SELECT v->>'fileKey' FROM owned CROSS JOIN LATERAL jsonb_array_elements(owned.variants) v
+		)
+		SELECT key FROM keys
+		WHERE key LIKE 'products/%'
+		AND NOT EXISTS (
+			SELECT 1 FROM product_images other
+			INNER JOIN products op ON op.id = other.product_id
+			INNER JOIN inventories oi ON oi.id = op.inventory_id
+			WHERE other.file_key = keys.key AND oi.user_id <> $1
+		)
+		UNION
+		SELECT avatar_key FROM users WHERE id = $1 AND avatar_key LIKE 'avatars/%'`, userId)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error retrieving stored files")
+	}
+
+	result, err := tx.Exec(`DELETE FROM users WHERE id = $1`, userId)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error deleting user")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, errors.NotFoundError("User not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return fileKeys, nil
+}
@@ -0,0 +1,192 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const maxAvatarSize = 2 << 20 // 2MB
+
+// Accepted avatar formats, keyed by sniffed content type
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type Service struct {
+	repo    IProfileRepository
+	users   auth.IAuthRepository
+	auth    auth.IAuthService
+	storage storage.IStorage
+}
+
+// NewService creates the profile service. storage may be nil when no bucket is configured, in
+// which case avatar uploads are rejected.
+func NewService(repo IProfileRepository, users auth.IAuthRepository, authService auth.IAuthService, storage storage.IStorage) IProfileService {
+	return &Service{repo: repo, users: users, auth: authService, storage: storage}
+}
+
+func (service *Service) UpdateProfile(user *models.User, req *models.UpdateProfileRequest) (*models.User, error) {
+	input := &models.UserRequest{Username: user.Username, Email: user.Email}
+	if req.Username != nil {
+		input.Username = *req.Username
+	}
+	if req.Email != nil {
+		input.Email = *req.Email
+	}
+	input.Sanitize()
+
+	if input.Username != user.Username {
+		exists, err := service.users.UsernameExists(input.Username)
+		if err != nil {
+			return nil, errors.InternalError(err, "Error checking username availability")
+		}
+		if exists {
+			return nil, errors.ConflictError("Username already taken")
+		}
+	}
+
+	emailChanged := input.Email != user.Email
+	if emailChanged {
+		exists, err := service.users.EmailExists(input.Email)
+		if err != nil {
+			return nil, errors.InternalError(err, "Error checking email availability")
+		}
+		if exists {
+			return nil, errors.ConflictError("Email already exists")
+		}
+	}
+
+	updated, err := service.repo.UpdateProfile(user.Id, input.Username, input.Email, emailChanged)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new address has to be confirmed before it counts as verified
+	if emailChanged {
+		if err := service.auth.ResendVerification(updated); err != nil {
+			logger.LogError("Error sending verification email", err, logger.Field("user_id", user.Id))
+		}
+	}
+
+	return updated, nil
+}
+
+// ChangePassword replaces the password after checking the current one, and signs out every
+// other session so a leaked password stops working everywhere
+func (service *Service) ChangePassword(user *models.User, sessionId uuid.UUID, req *models.ChangePasswordRequest) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return errors.UnauthorizedError("Current password is incorrect")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.InternalError(err, "Error hashing password")
+	}
+
+	if err := service.repo.UpdatePassword(user.Id, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	return service.auth.RevokeUserSessions(user.Id, sessionId, models.RevokedPasswordChange)
+}
+
+func (service *Service) UploadAvatar(ctx context.Context, user *models.User, file io.Reader, size int64) (*models.User, error) {
+	if service.storage == nil {
+		return nil, errors.New(errors.InternalErr, "File storage is not configured", http.StatusServiceUnavailable)
+	}
+
+	if size > maxAvatarSize {
+		return nil, errors.ValidationError("Avatar must be 2MB or smaller")
+	}
+
+	// Trust the file contents rather than the client-supplied content type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, errors.ValidationError("Unable to read avatar file")
+	}
+	head = head[:n]
+
+	ext, ok := avatarExtensions[http.DetectContentType(head)]
+	if !ok {
+		return nil, errors.ValidationError("Avatar must be a JPEG, PNG, GIF or WebP image")
+	}
+
+	result, err := service.storage.Upload(ctx, io.MultiReader(bytes.NewReader(head), file), "avatars", "avatar"+ext)
+	if err != nil {
+		return nil, errors.InternalError(err, "Error uploading avatar")
+	}
+
+	if err := service.repo.UpdateAvatar(user.Id, &result.URL, &result.FileKey); err != nil {
+		service.deleteObjects(ctx, result.FileKey)
+		return nil, err
+	}
+
+	if user.AvatarKey != nil {
+		service.deleteObjects(ctx, *user.AvatarKey)
+	}
+
+	user.Avatar = &result.URL
+	user.AvatarKey = &result.FileKey
+	return user, nil
+}
+
+func (service *Service) RemoveAvatar(ctx context.Context, user *models.User) error {
+	if err := service.repo.UpdateAvatar(user.Id, nil, nil); err != nil {
+		return err
+	}
+
+	if user.AvatarKey != nil {
+		service.deleteObjects(ctx, *user.AvatarKey)
+	}
+
+	return nil
+}
+
+// DeleteAccount permanently removes the user along with the inventories they own
+func (service *Service) DeleteAccount(user *models.User, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.UnauthorizedError("Password is incorrect")
+	}
+
+	// Revoke first so cached session state cannot outlive the account
+	if err := service.auth.RevokeUserSessions(user.Id, uuid.Nil, models.RevokedAccountDeleted); err != nil {
+		return err
+	}
+
+	fileKeys, err := service.repo.DeleteUser(user.Id)
+	if err != nil {
+		return err
+	}
+
+	// The rows are gone, so bucket cleanup must not be tied to the request's lifetime
+	go service.deleteObjects(context.Background(), fileKeys...)
+
+	return nil
+}
+
+// deleteObjects removes stored files best-effort; a leftover object is only wasted space
+func (service *Service) deleteObjects(ctx context.Context, fileKeys ...string) {
+	if service.storage == nil {
+		return
+	}
+
+	for _, key := range fileKeys {
+		if err := service.storage.Delete(ctx, key); err != nil {
+			logger.LogError("Error deleting stored file", err, logger.Field("file_key", key))
+		}
+	}
+}
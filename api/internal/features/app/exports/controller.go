@@ -0,0 +1,183 @@
+package exports
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/mappers"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type ExportController struct {
+	repo    IExportRepository
+	service IExportService
+	jobs    *JobRunner
+}
+
+func NewExportController(repo IExportRepository, service IExportService, jobs *JobRunner) *ExportController {
+	return &ExportController{
+		repo:    repo,
+		service: service,
+		jobs:    jobs,
+	}
+}
+
+// ExportData streams an export straight into the response
+func (ec *ExportController) ExportData(ctx echo.Context) error {
+	query, err := ec.bindExportQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := ctx.Response()
+	response.Header().Set(echo.HeaderContentType, ContentType(query.Format))
+	response.Header().Set(echo.HeaderContentDisposition,
+		`attachment; filename="`+mappers.ExportFileName(query.Type, query.Format, time.Now())+`"`)
+
+	if _, err := ec.service.Export(ctx.Request().Context(), query, response); err != nil {
+		appErr := logger.Error(ctx, "Failed to stream export", err,
+			logger.Field("inventory_id", query.InventoryID),
+			logger.Field("type", query.Type),
+		)
+
+		// Once rows have been sent the status line is gone and the failure can only be logged
+		if response.Committed {
+			return nil
+		}
+		response.Header().Del(echo.HeaderContentType)
+		response.Header().Del(echo.HeaderContentDisposition)
+		return appErr
+	}
+
+	return nil
+}
+
+func (ec *ExportController) CreateExportJob(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	query, err := ec.bindExportQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	job, err := mappers.ToExportJob(query, user.Id, JobTTL)
+	if err != nil {
+		return errors.InternalError(err, "Failed to encode export query")
+	}
+
+	if err := ec.repo.CreateJob(job); err != nil {
+		return logger.Error(ctx, "Failed to create export job", err,
+			logger.Field("inventory_id", query.InventoryID),
+		)
+	}
+
+	ec.jobs.Enqueue(job.ID)
+
+	response := mappers.ToExportJobResponse(job)
+	return ctx.JSON(http.StatusAccepted, response)
+}
+
+func (ec *ExportController) ListExportJobs(ctx echo.Context) error {
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	jobs, err := ec.repo.ListJobs(inventoryID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch export jobs", err,
+			logger.Field("inventory_id", inventoryID),
+		)
+	}
+
+	response := make([]*models.ExportJobResponse, len(jobs))
+	for i, job := range jobs {
+		response[i] = mappers.ToExportJobResponse(&job)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ec *ExportController) GetExportJob(ctx echo.Context) error {
+	job, err := ec.findJob(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := mappers.ToExportJobResponse(job)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (ec *ExportController) DownloadExportJob(ctx echo.Context) error {
+	job, err := ec.findJob(ctx)
+	if err != nil {
+		return err
+	}
+
+	if job.Status != models.ExportJobCompleted || job.FilePath == nil {
+		return errors.ConflictError("Export is not ready for download")
+	}
+
+	if _, err := os.Stat(*job.FilePath); err != nil {
+		return errors.NotFoundError("Export file has expired")
+	}
+
+	return ctx.Attachment(*job.FilePath, mappers.ExportFileName(job.Type, job.Format, job.CreatedAt))
+}
+
+// Helper methods
+func (ec *ExportController) bindExportQuery(ctx echo.Context) (*models.ExportQuery, error) {
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return nil, errors.ValidationError("Invalid inventory ID")
+	}
+
+	exportType := ctx.Param("type")
+	switch exportType {
+	case models.ExportProducts, models.ExportWarehouses, models.ExportMovements:
+	default:
+		return nil, errors.ValidationError("Export type must be products, warehouses or movements")
+	}
+
+	var req models.ExportRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	return mappers.ToExportQuery(&req, inventoryID, exportType), nil
+}
+
+// findJob loads a job from the path, hiding jobs that belong to another inventory or expired
+func (ec *ExportController) findJob(ctx echo.Context) (*models.ExportJob, error) {
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return nil, errors.ValidationError("Invalid inventory ID")
+	}
+
+	jobID, err := uuid.Parse(ctx.Param("jobId"))
+	if err != nil {
+		return nil, errors.ValidationError("Invalid export job ID")
+	}
+
+	job, err := ec.repo.GetJob(jobID)
+	if err != nil {
+		return nil, logger.Error(ctx, "Failed to fetch export job", err,
+			logger.Field("job_id", jobID),
+		)
+	}
+
+	if job.InventoryID != inventoryID || time.Now().After(job.ExpiresAt) {
+		return nil, errors.NotFoundError("Export job not found")
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,38 @@
+package exports
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type IExportRepository interface {
+	StreamProducts(ctx context.Context, filter *models.ProductFilter, emit func(*models.ProductExportRow) error) error
+	StreamWarehouses(ctx context.Context, inventoryId uuid.UUID, emit func(*models.WarehouseExportRow) error) error
+	StreamMovements(ctx context.Context, query *models.ExportQuery, emit func(*models.MovementExportRow) error) error
+
+	CreateJob(job *models.ExportJob) error
+	GetJob(jobId uuid.UUID) (models.ExportJob, error)
+	ListJobs(inventoryId uuid.UUID) ([]models.ExportJob, error)
+	ClaimJob(jobId uuid.UUID) (bool, error)
+	CompleteJob(jobId uuid.UUID, filePath string, rowCount int) error
+	FailJob(jobId uuid.UUID, message string) error
+	RequeueInterruptedJobs() error
+	GetPendingJobIDs(limit int) ([]uuid.UUID, error)
+	DeleteExpiredJobs() ([]string, error)
+}
+
+type IExportService interface {
+	Export(ctx context.Context, query *models.ExportQuery, w io.Writer) (int, error)
+}
+
+type IExportController interface {
+	ExportData(ctx echo.Context) error
+	CreateExportJob(ctx echo.Context) error
+	ListExportJobs(ctx echo.Context) error
+	GetExportJob(ctx echo.Context) error
+	DownloadExportJob(ctx echo.Context) error
+}
@@ -0,0 +1,201 @@
+package exports
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/logger"
+)
+
+const (
+	// JobTTL is how long a finished export stays downloadable
+	JobTTL = 24 * time.Hour
+
+	jobWorkers       = 2
+	jobQueueSize     = 100
+	jobSweepInterval = time.Minute
+	cleanupInterval  = time.Hour
+)
+
+// JobRunner executes queued export jobs in the background and writes their results to dir
+type JobRunner struct {
+	repo    IExportRepository
+	service IExportService
+	dir     string
+	queue   chan uuid.UUID
+}
+
+func NewJobRunner(repo IExportRepository, service IExportService, dir string) *JobRunner {
+	return &JobRunner{
+		repo:    repo,
+		service: service,
+		dir:     dir,
+		queue:   make(chan uuid.UUID, jobQueueSize),
+	}
+}
+
+// Start launches the workers, the cleanup loop and the sweep that picks up pending jobs,
+// including those a previous process left unfinished
+func (jr *JobRunner) Start(ctx context.Context) {
+	if err := os.MkdirAll(jr.dir, 0o750); err != nil {
+		logger.LogError("Failed to create export directory", err, logger.Field("dir", jr.dir))
+	}
+
+	for i := 0; i < jobWorkers; i++ {
+		go jr.work(ctx)
+	}
+	go jr.cleanup(ctx)
+
+	go func() {
+		if err := jr.repo.RequeueInterruptedJobs(); err != nil {
+			logger.LogError("Failed to requeue export jobs", err)
+		}
+		jr.sweep(ctx)
+	}()
+}
+
+// Enqueue hands a pending job to the workers without blocking the caller
+func (jr *JobRunner) Enqueue(jobID uuid.UUID) {
+	select {
+	case jr.queue <- jobID:
+	default:
+		// The job stays pending; the sweep picks it up once the queue drains
+	}
+}
+
+// FilePath returns where the result of a job is written
+func (jr *JobRunner) FilePath(jobID uuid.UUID, format string) string {
+	return filepath.Join(jr.dir, jobID.String()+"."+format)
+}
+
+func (jr *JobRunner) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case jobID := <-jr.queue:
+			jr.run(ctx, jobID)
+		}
+	}
+}
+
+func (jr *JobRunner) run(ctx context.Context, jobID uuid.UUID) {
+	claimed, err := jr.repo.ClaimJob(jobID)
+	if err != nil || !claimed {
+		return
+	}
+
+	job, err := jr.repo.GetJob(jobID)
+	if err != nil {
+		logger.LogError("Failed to load export job", err, logger.Field("job_id", jobID))
+		return
+	}
+
+	var query models.ExportQuery
+	if err := json.Unmarshal(job.Query, &query); err != nil {
+		jr.fail(jobID, "Invalid export query", err)
+		return
+	}
+
+	path := jr.FilePath(job.ID, job.Format)
+	rows, err := jr.writeFile(ctx, path, &query)
+	if err != nil {
+		os.Remove(path)
+		jr.fail(jobID, "Export failed", err)
+		return
+	}
+
+	if err := jr.repo.CompleteJob(jobID, path, rows); err != nil {
+		logger.LogError("Failed to complete export job", err, logger.Field("job_id", jobID))
+	}
+}
+
+func (jr *JobRunner) writeFile(ctx context.Context, path string, query *models.ExportQuery) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	rows, err := jr.service.Export(ctx, query, buffered)
+	if err != nil {
+		return rows, err
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return rows, err
+	}
+	return rows, file.Close()
+}
+
+func (jr *JobRunner) fail(jobID uuid.UUID, message string, err error) {
+	logger.LogError(message, err, logger.Field("job_id", jobID))
+	if err := jr.repo.FailJob(jobID, message); err != nil {
+		logger.LogError("Failed to mark export job as failed", err, logger.Field("job_id", jobID))
+	}
+}
+
+// sweep queues pending jobs whenever the workers have caught up
+func (jr *JobRunner) sweep(ctx context.Context) {
+	ticker := time.NewTicker(jobSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if len(jr.queue) == 0 {
+			ids, err := jr.repo.GetPendingJobIDs(jobQueueSize)
+			if err != nil {
+				logger.LogError("Failed to fetch pending export jobs", err)
+			}
+			for _, id := range ids {
+				jr.Enqueue(id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup periodically removes expired jobs and their files. Files whose job disappeared
+// with its inventory are swept once they outlive the TTL.
+func (jr *JobRunner) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		files, err := jr.repo.DeleteExpiredJobs()
+		if err != nil {
+			logger.LogError("Failed to delete expired export jobs", err)
+		}
+		for _, file := range files {
+			os.Remove(file)
+		}
+
+		entries, err := os.ReadDir(jr.dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err == nil && time.Since(info.ModTime()) > JobTTL+cleanupInterval {
+				os.Remove(filepath.Join(jr.dir, entry.Name()))
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package exports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/features/app/products"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type ExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewExportRepository(db *sqlx.DB) IExportRepository {
+	return &ExportRepository{db: db}
+}
+
+func (er *ExportRepository) StreamProducts(ctx context.Context, filter *models.ProductFilter, emit func(*models.ProductExportRow) error) error {
+	where, args := products.ProductFilterClause(filter)
+
+	query := `
+		SELECT p.id, p.name, p.sku, p.code, p.brand, p.model, p.description,
+			p.quantity, p.restock_level, p.optimal_level, p.cost, p.price,
+			p.created_at, p.updated_at,
+			(
+				SELECT string_agg(c.name, '; ' ORDER BY c.name)
+				FROM product_category_map m
+				INNER JOIN product_categories c ON c.id = m.category_id
+				WHERE m.product_id = p.id
+			) AS categories,
+			(
+				SELECT string_agg(w.name || ': ' || wp.quantity, '; ' ORDER BY w.name)
+				FROM warehouse_product_map wp
+				INNER JOIN warehouses w ON w.id = wp.warehouse_id
+				WHERE wp.product_id = p.id
+			) AS warehouse_stock,
+			(
+				SELECT i.url FROM product_images i
+				WHERE i.product_id = p.id
+				ORDER BY i.is_primary DESC, i.created_at ASC
+				LIMIT 1
+			) AS primary_image_url
+		FROM products p
+		WHERE ` + where + `
+		ORDER BY p.name ASC, p.id ASC`
+
+	return stream(ctx, er.db, query, args, func(rows *sqlx.Rows) error {
+		var row models.ProductExportRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		return emit(&row)
+	})
+}
+
+func (er *ExportRepository) StreamWarehouses(ctx context.Context, inventoryID uuid.UUID, emit func(*models.WarehouseExportRow) error) error {
+	query := `
+		SELECT w.id, w.name, w.location, w.capacity, w.created_at, w.updated_at,
+			COUNT(wp.product_id) AS product_count,
+			COALESCE(SUM(wp.quantity), 0) AS stock_total
+		FROM warehouses w
+		LEFT JOIN warehouse_product_map wp ON wp.warehouse_id = w.id
+		WHERE w.inventory_id = $1
+		GROUP BY w.id
+		ORDER BY w.name ASC`
+
+	return stream(ctx, er.db, query, []interface{}{inventoryID}, func(rows *sqlx.Rows) error {
+		var row models.WarehouseExportRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		return emit(&row)
+	})
+}
+
+func (er *ExportRepository) StreamMovements(ctx context.Context, query *models.ExportQuery, emit func(*models.MovementExportRow) error) error {
+	conditions := []string{"m.inventory_id = $1"}
+	args := []interface{}{query.InventoryID}
+
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if query.ProductID != nil {
+		add("m.product_id = $%d", *query.ProductID)
+	}
+	if query.WarehouseID != nil {
+		add("m.warehouse_id = $%d", *query.WarehouseID)
+	}
+	if query.Reason != "" {
+		add("m.reason = $%d", query.Reason)
+	}
+	if query.From != nil {
+		add("m.created_at >= $%d", *query.From)
+	}
+	if query.To != nil {
+		add("m.created_at < $%d", *query.To)
+	}
+
+	sqlQuery := `
+		SELECT m.id, m.created_at, m.product_id, p.name AS product_name, p.sku AS product_sku,
+			m.warehouse_id, w.name AS warehouse_name, m.delta, m.balance, m.reason, m.reference,
+			u.username
+		FROM stock_movements m
+		LEFT JOIN products p ON p.id = m.product_id
+		LEFT JOIN warehouses w ON w.id = m.warehouse_id
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY m.created_at ASC, m.id ASC`
+
+	return stream(ctx, er.db, sqlQuery, args, func(rows *sqlx.Rows) error {
+		var row models.MovementExportRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		return emit(&row)
+	})
+}
+
+func (er *ExportRepository) CreateJob(job *models.ExportJob) error {
+	query := `
+		INSERT INTO export_jobs (
+			id, inventory_id, user_id, type, format, status, query, created_at, expires_at
+		) VALUES (
+			:id, :inventory_id, :user_id, :type, :format, :status, :query, :created_at, :expires_at
+		)`
+
+	if _, err := er.db.NamedExec(query, job); err != nil {
+		return errors.DatabaseError(err, "Error creating export job")
+	}
+
+	return nil
+}
+
+func (er *ExportRepository) GetJob(jobID uuid.UUID) (models.ExportJob, error) {
+	var job models.ExportJob
+	err := er.db.Get(&job, `SELECT * FROM export_jobs WHERE id = $1`, jobID)
+	if err == sql.ErrNoRows {
+		return job, errors.NotFoundError("Export job not found")
+	} else if err != nil {
+		return job, errors.DatabaseError(err, "Error fetching export job")
+	}
+
+	return job, nil
+}
+
+func (er *ExportRepository) ListJobs(inventoryID uuid.UUID) ([]models.ExportJob, error) {
+	jobs := []models.ExportJob{}
+	query := `
+		SELECT * FROM export_jobs
+		WHERE inventory_id = $1 AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 50`
+
+	if err := er.db.Select(&jobs, query, inventoryID); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching export jobs")
+	}
+
+	return jobs, nil
+}
+
+// ClaimJob moves a pending job to running; it reports false if another worker got there first
+func (er *ExportRepository) ClaimJob(jobID uuid.UUID) (bool, error) {
+	result, err := er.db.Exec(`UPDATE export_jobs SET status = $2 WHERE id = $1 AND status = $3`,
+		jobID, models.ExportJobRunning, models.ExportJobPending)
+	if err != nil {
+		return false, errors.DatabaseError(err, "Error claiming export job")
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+func (er *ExportRepository) CompleteJob(jobID uuid.UUID, filePath string, rowCount int) error {
+	_, err := er.db.Exec(`
+		UPDATE export_jobs SET status = $2, file_path = $3, row_count = $4, completed_at = $5
+		WHERE id = $1`, jobID, models.ExportJobCompleted, filePath, rowCount, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error completing export job")
+	}
+
+	return nil
+}
+
+func (er *ExportRepository) FailJob(jobID uuid.UUID, message string) error {
+	_, err := er.db.Exec(`
+		UPDATE export_jobs SET status = $2, error = $3, completed_at = $4
+		WHERE id = $1`, jobID, models.ExportJobFailed, message, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error failing export job")
+	}
+
+	return nil
+}
+
+// RequeueInterruptedJobs puts jobs a previous process was running back in line
+func (er *ExportRepository) RequeueInterruptedJobs() error {
+	_, err := er.db.Exec(`UPDATE export_jobs SET status = $1 WHERE status = $2`,
+		models.ExportJobPending, models.ExportJobRunning)
+	if err != nil {
+		return errors.DatabaseError(err, "Error requeuing export jobs")
+	}
+
+	return nil
+}
+
+func (er *ExportRepository) GetPendingJobIDs(limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := er.db.Select(&ids, `SELECT id FROM export_jobs WHERE status = $1 ORDER BY created_at ASC LIMIT $2`,
+		models.ExportJobPending, limit)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching pending export jobs")
+	}
+
+	return ids, nil
+}
+
+// DeleteExpiredJobs removes expired jobs and returns the result files they leave behind
+func (er *ExportRepository) DeleteExpiredJobs() ([]string, error) {
+	var paths []sql.NullString
+	err := er.db.Select(&paths, `DELETE FROM export_jobs WHERE expires_at <= NOW() RETURNING file_path`)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error deleting expired export jobs")
+	}
+
+	var files []string
+	for _, path := range paths {
+		if path.Valid {
+			files = append(files, path.String)
+		}
+	}
+
+	return files, nil
+}
+
+// stream runs query and hands rows to scan one at a time, so exports never hold the full
+// result set in memory
+func stream(ctx context.Context, db *sqlx.DB, query string, args []interface{}, scan func(rows *sqlx.Rows) error) error {
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return errors.DatabaseError(err, "Error running export query")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.DatabaseError(err, "Error reading export rows")
+	}
+
+	return nil
+}
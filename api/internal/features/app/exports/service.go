@@ -0,0 +1,99 @@
+package exports
+
+import (
+	"context"
+	"io"
+
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+// Rows written between flushes of a streamed response
+const flushEvery = 500
+
+var (
+	productColumns = []string{
+		"id", "name", "sku", "code", "brand", "model", "description", "quantity",
+		"restockLevel", "optimalLevel", "cost", "price", "categories", "warehouseStock",
+		"primaryImageUrl", "createdAt", "updatedAt",
+	}
+	warehouseColumns = []string{
+		"id", "name", "location", "capacity", "productCount", "stockTotal", "createdAt", "updatedAt",
+	}
+	movementColumns = []string{
+		"id", "createdAt", "productId", "productName", "productSku", "warehouseId", "warehouseName",
+		"delta", "balance", "reason", "reference", "user",
+	}
+)
+
+type ExportService struct {
+	repo IExportRepository
+}
+
+func NewExportService(repo IExportRepository) IExportService {
+	return &ExportService{repo: repo}
+}
+
+// Export streams the dataset described by query to w and returns the number of rows written
+func (es *ExportService) Export(ctx context.Context, query *models.ExportQuery, w io.Writer) (int, error) {
+	rw, err := newRowWriter(query.Format, w)
+	if err != nil {
+		return 0, errors.ValidationError("Unsupported export format")
+	}
+	// Early returns below skip close; the writer must not keep temporary files around
+	defer rw.abort()
+
+	count := 0
+	write := func(values ...interface{}) error {
+		if err := rw.row(values); err != nil {
+			return errors.InternalError(err, "Error writing export row")
+		}
+		count++
+		if count%flushEvery == 0 {
+			return rw.flush()
+		}
+		return nil
+	}
+
+	switch query.Type {
+	case models.ExportProducts:
+		if err := rw.header(productColumns); err != nil {
+			return 0, err
+		}
+		err = es.repo.StreamProducts(ctx, query.Products, func(p *models.ProductExportRow) error {
+			return write(p.ID, p.Name, p.SKU, p.Code, p.Brand, p.Model, p.Description, p.Quantity,
+				p.RestockLevel, p.OptimalLevel, float64(p.Cost)/100, float64(p.Price)/100, p.Categories,
+				p.WarehouseStock, p.PrimaryImageURL, p.CreatedAt, p.UpdatedAt)
+		})
+
+	case models.ExportWarehouses:
+		if err := rw.header(warehouseColumns); err != nil {
+			return 0, err
+		}
+		err = es.repo.StreamWarehouses(ctx, query.InventoryID, func(w *models.WarehouseExportRow) error {
+			return write(w.ID, w.Name, w.Location, w.Capacity, w.ProductCount, w.StockTotal, w.CreatedAt, w.UpdatedAt)
+		})
+
+	case models.ExportMovements:
+		if err := rw.header(movementColumns); err != nil {
+			return 0, err
+		}
+		err = es.repo.StreamMovements(ctx, query, func(m *models.MovementExportRow) error {
+			return write(m.ID, m.CreatedAt, m.ProductID, m.ProductName, m.ProductSKU, m.WarehouseID,
+				m.WarehouseName, m.Delta, m.Balance, m.Reason, m.Reference, m.Username)
+		})
+
+	default:
+		return 0, errors.ValidationError("Unknown export type")
+	}
+
+	if err != nil {
+		return count, err
+	}
+
+	if err := rw.close(); err != nil {
+		return count, errors.InternalError(err, "Error finishing export")
+	}
+
+	return count, nil
+}
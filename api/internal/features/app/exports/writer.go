@@ -0,0 +1,224 @@
+package exports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// rowWriter encodes export rows in one output format. abort releases what the writer holds
+// when the export stops before close; it is safe to call after close.
+type rowWriter interface {
+	header(columns []string) error
+	row(values []interface{}) error
+	flush() error
+	close() error
+	abort()
+}
+
+func newRowWriter(format string, w io.Writer) (rowWriter, error) {
+	switch format {
+	case models.ExportFormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), out: w}, nil
+	case models.ExportFormatJSON:
+		return &jsonWriter{w: w}, nil
+	case models.ExportFormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case models.ExportFormatJSON:
+		return "application/json"
+	case models.ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// exportValue flattens pointers, IDs and timestamps into plain cell values
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *int:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case uuid.UUID:
+		return v.String()
+	case *uuid.UUID:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return v
+	}
+}
+
+// CSV
+type csvWriter struct {
+	w   *csv.Writer
+	out io.Writer
+}
+
+func (cw *csvWriter) header(columns []string) error {
+	return cw.w.Write(columns)
+}
+
+func (cw *csvWriter) row(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if v := exportValue(value); v != nil {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) flush() error {
+	cw.w.Flush()
+	if flusher, ok := cw.out.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) abort() {}
+
+// JSON, written as one array of objects keyed by column
+type jsonWriter struct {
+	w       io.Writer
+	columns []string
+	rows    int
+}
+
+func (jw *jsonWriter) header(columns []string) error {
+	jw.columns = columns
+	_, err := io.WriteString(jw.w, "[")
+	return err
+}
+
+func (jw *jsonWriter) row(values []interface{}) error {
+	separator := "\n"
+	if jw.rows > 0 {
+		separator = ",\n"
+	}
+	jw.rows++
+
+	buf := []byte(separator + "{")
+	for i, column := range jw.columns {
+		key, _ := json.Marshal(column)
+		value, err := json.Marshal(exportValue(values[i]))
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(append(append(buf, key...), ':'), value...)
+	}
+	buf = append(buf, '}')
+
+	_, err := jw.w.Write(buf)
+	return err
+}
+
+func (jw *jsonWriter) flush() error {
+	if flusher, ok := jw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (jw *jsonWriter) close() error {
+	_, err := io.WriteString(jw.w, "\n]\n")
+	return err
+}
+
+func (jw *jsonWriter) abort() {}
+
+// XLSX. The workbook is assembled with excelize's stream writer, which spills rows to a
+// temporary file instead of memory; the zip container can only be written once complete.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rows   int
+}
+
+func newXLSXWriter(out io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: out, file: file, stream: stream}, nil
+}
+
+func (xw *xlsxWriter) header(columns []string) error {
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	return xw.row(values)
+}
+
+func (xw *xlsxWriter) row(values []interface{}) error {
+	xw.rows++
+	cell, err := excelize.CoordinatesToCellName(1, xw.rows)
+	if err != nil {
+		return err
+	}
+
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = exportValue(value)
+	}
+	return xw.stream.SetRow(cell, cells)
+}
+
+func (xw *xlsxWriter) flush() error {
+	return nil
+}
+
+func (xw *xlsxWriter) close() error {
+	defer xw.abort()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
+
+// abort removes the stream writer's temporary files
+func (xw *xlsxWriter) abort() {
+	if xw.file != nil {
+		xw.file.Close()
+		xw.file = nil
+	}
+}
@@ -0,0 +1,56 @@
+package movements
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/mappers"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type MovementController struct {
+	repo IMovementRepository
+}
+
+func NewMovementController(repo IMovementRepository) IMovementController {
+	return &MovementController{repo: repo}
+}
+
+func (mc *MovementController) ListProductMovements(ctx echo.Context) error {
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	page, limit := utils.ParsePagination(ctx)
+	movements, total, err := mc.repo.ListProductMovements(productID, limit, (page-1)*limit)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch product stock movements", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	response := mappers.ToStockMovementPage(movements, total, page, limit)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (mc *MovementController) ListWarehouseMovements(ctx echo.Context) error {
+	warehouseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid warehouse ID")
+	}
+
+	page, limit := utils.ParsePagination(ctx)
+	movements, total, err := mc.repo.ListWarehouseMovements(warehouseID, limit, (page-1)*limit)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch warehouse stock movements", err,
+			logger.Field("warehouse_id", warehouseID),
+		)
+	}
+
+	response := mappers.ToStockMovementPage(movements, total, page, limit)
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,28 @@
+package movements
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+// IStockLedger writes quantity changes and their movement records inside the caller's transaction.
+type IStockLedger interface {
+	AdjustStock(tx *sqlx.Tx, change *models.StockChange) (*models.StockMovement, error)
+	SetStock(tx *sqlx.Tx, change *models.StockChange, quantity int) (*models.StockMovement, error)
+	IsWarehouseStocked(tx *sqlx.Tx, productID uuid.UUID) (bool, error)
+	SyncProductQuantity(tx *sqlx.Tx, productID uuid.UUID) error
+	LinkWarehouse(tx *sqlx.Tx, productID, warehouseID uuid.UUID, userID *uuid.UUID) error
+}
+
+type IMovementRepository interface {
+	IStockLedger
+	ListProductMovements(productID uuid.UUID, limit, offset int) ([]models.StockMovement, int, error)
+	ListWarehouseMovements(warehouseID uuid.UUID, limit, offset int) ([]models.StockMovement, int, error)
+}
+
+type IMovementController interface {
+	ListProductMovements(ctx echo.Context) error
+	ListWarehouseMovements(ctx echo.Context) error
+}
@@ -0,0 +1,269 @@
+package movements
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type MovementRepository struct {
+	db *sqlx.DB
+}
+
+func NewMovementRepository(db *sqlx.DB) IMovementRepository {
+	return &MovementRepository{db: db}
+}
+
+// stockLevel is the locked state a ledger change is applied against
+type stockLevel struct {
+	Quantity    int           `db:"quantity"`
+	InventoryID uuid.UUID     `db:"inventory_id"`
+	Capacity    sql.NullInt64 `db:"capacity"`
+	Stored      int           `db:"-"`
+}
+
+// AdjustStock applies a signed delta to the product or warehouse quantity and appends
+// the matching ledger entry. Changes that would drive stock below zero or past a
+// warehouse's capacity are rejected.
+func (mr *MovementRepository) AdjustStock(tx *sqlx.Tx, change *models.StockChange) (*models.StockMovement, error) {
+	level, err := mr.lockStock(tx, change)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr.apply(tx, change, level, level.Quantity+change.Delta)
+}
+
+// SetStock overwrites the product or warehouse quantity, recording the difference as the delta.
+func (mr *MovementRepository) SetStock(tx *sqlx.Tx, change *models.StockChange, quantity int) (*models.StockMovement, error) {
+	level, err := mr.lockStock(tx, change)
+	if err != nil {
+		return nil, err
+	}
+
+	change.Delta = quantity - level.Quantity
+	return mr.apply(tx, change, level, quantity)
+}
+
+// IsWarehouseStocked reports whether a product's quantity is derived from its warehouse links.
+func (mr *MovementRepository) IsWarehouseStocked(tx *sqlx.Tx, productID uuid.UUID) (bool, error) {
+	var stocked bool
+	err := tx.Get(&stocked, `SELECT EXISTS(SELECT 1 FROM warehouse_product_map WHERE product_id = $1)`, productID)
+	if err != nil {
+		return false, errors.DatabaseError(err, "Error checking product warehouses")
+	}
+
+	return stocked, nil
+}
+
+// SyncProductQuantity recalculates a warehouse-stocked product's quantity from its warehouse rows.
+func (mr *MovementRepository) SyncProductQuantity(tx *sqlx.Tx, productID uuid.UUID) error {
+	_, err := tx.Exec(`
+		UPDATE products SET quantity = (
+			SELECT COALESCE(SUM(quantity), 0) FROM warehouse_product_map WHERE product_id = $1
+		), updated_at = $2
+		WHERE id = $1 AND EXISTS(SELECT 1 FROM warehouse_product_map WHERE product_id = $1)
+	`, productID, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error syncing product quantity")
+	}
+
+	return nil
+}
+
+// LinkWarehouse stocks a product in a warehouse of its inventory. When this is the product's
+// first warehouse, its unassigned product-level quantity moves into the new warehouse so the
+// derived total does not change.
+func (mr *MovementRepository) LinkWarehouse(tx *sqlx.Tx, productID, warehouseID uuid.UUID, userID *uuid.UUID) error {
+	stocked, err := mr.IsWarehouseStocked(tx, productID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO warehouse_product_map (product_id, warehouse_id, quantity, created_at)
+		SELECT p.id, w.id, 0, $3 FROM products p
+		INNER JOIN warehouses w ON w.inventory_id = p.inventory_id
+		WHERE p.id = $1 AND w.id = $2
+		ON CONFLICT (product_id, warehouse_id) DO NOTHING
+	`, productID, warehouseID, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error linking product to warehouse")
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		var exists bool
+		err := tx.Get(&exists, `SELECT EXISTS(SELECT 1 FROM warehouse_product_map WHERE product_id = $1 AND warehouse_id = $2)`,
+			productID, warehouseID)
+		if err != nil {
+			return errors.DatabaseError(err, "Error linking product to warehouse")
+		}
+		if !exists {
+			return errors.NotFoundError("Warehouse not found in this inventory")
+		}
+		return nil
+	}
+
+	if stocked {
+		return nil
+	}
+
+	var unassigned int
+	if err := tx.Get(&unassigned, `SELECT quantity FROM products WHERE id = $1 FOR UPDATE`, productID); err != nil {
+		return errors.DatabaseError(err, "Error locking product stock")
+	}
+
+	reference := "Unassigned stock"
+	change := &models.StockChange{
+		ProductID:   productID,
+		WarehouseID: &warehouseID,
+		Delta:       unassigned,
+		Reason:      models.ReasonTransfer,
+		Reference:   &reference,
+		UserID:      userID,
+	}
+	_, err = mr.AdjustStock(tx, change)
+	return err
+}
+
+func (mr *MovementRepository) ListProductMovements(productID uuid.UUID, limit, offset int) ([]models.StockMovement, int, error) {
+	return mr.listMovements("product_id", productID, limit, offset)
+}
+
+func (mr *MovementRepository) ListWarehouseMovements(warehouseID uuid.UUID, limit, offset int) ([]models.StockMovement, int, error) {
+	return mr.listMovements("warehouse_id", warehouseID, limit, offset)
+}
+
+// Helper methods
+func (mr *MovementRepository) lockStock(tx *sqlx.Tx, change *models.StockChange) (*stockLevel, error) {
+	var level stockLevel
+
+	if change.WarehouseID == nil {
+		err := tx.Get(&level, `
+			SELECT quantity, inventory_id FROM products
+			WHERE id = $1 FOR UPDATE
+		`, change.ProductID)
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundError("Product not found")
+		} else if err != nil {
+			return nil, errors.DatabaseError(err, "Error locking stock")
+		}
+
+		// Once a product is stocked in warehouses its quantity is derived from them
+		stocked, err := mr.IsWarehouseStocked(tx, change.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if stocked {
+			return nil, errors.ValidationError("Product stock is managed per warehouse")
+		}
+
+		return &level, nil
+	}
+
+	// Lock the warehouse row so concurrent changes cannot overrun its capacity
+	err := tx.Get(&level, `
+		SELECT wp.quantity, w.inventory_id, w.capacity
+		FROM warehouses w
+		INNER JOIN warehouse_product_map wp ON wp.warehouse_id = w.id
+		WHERE wp.product_id = $1 AND w.id = $2
+		FOR UPDATE
+	`, change.ProductID, *change.WarehouseID)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundError("Product is not stocked in this warehouse")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error locking stock")
+	}
+
+	if level.Capacity.Valid {
+		err = tx.Get(&level.Stored, `SELECT COALESCE(SUM(quantity), 0) FROM warehouse_product_map WHERE warehouse_id = $1`,
+			*change.WarehouseID)
+		if err != nil {
+			return nil, errors.DatabaseError(err, "Error calculating warehouse stock")
+		}
+	}
+
+	return &level, nil
+}
+
+func (mr *MovementRepository) apply(tx *sqlx.Tx, change *models.StockChange, level *stockLevel, balance int) (*models.StockMovement, error) {
+	current := level.Quantity
+	if balance == current {
+		return nil, nil
+	}
+	if balance < 0 {
+		return nil, errors.ValidationError("Insufficient stock for this change")
+	}
+	if level.Capacity.Valid && balance > current && int64(level.Stored+balance-current) > level.Capacity.Int64 {
+		return nil, errors.ValidationError("Change exceeds warehouse capacity")
+	}
+
+	var err error
+	if change.WarehouseID == nil {
+		_, err = tx.Exec(`UPDATE products SET quantity = $1, updated_at = $2 WHERE id = $3`,
+			balance, time.Now(), change.ProductID)
+	} else {
+		_, err = tx.Exec(`UPDATE warehouse_product_map SET quantity = $1 WHERE product_id = $2 AND warehouse_id = $3`,
+			balance, change.ProductID, *change.WarehouseID)
+		if err == nil {
+			err = mr.SyncProductQuantity(tx, change.ProductID)
+		}
+	}
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error updating stock quantity")
+	}
+
+	productID := change.ProductID
+	movement := &models.StockMovement{
+		ID:          uuid.New(),
+		ProductID:   &productID,
+		WarehouseID: change.WarehouseID,
+		InventoryID: level.InventoryID,
+		Delta:       balance - current,
+		Balance:     balance,
+		Reason:      change.Reason,
+		Reference:   change.Reference,
+		UserID:      change.UserID,
+		CreatedAt:   time.Now(),
+	}
+
+	query := `
+		INSERT INTO stock_movements (
+			id, product_id, warehouse_id, inventory_id, delta, balance,
+			reason, reference, user_id, created_at
+		) VALUES (
+			:id, :product_id, :warehouse_id, :inventory_id, :delta, :balance,
+			:reason, :reference, :user_id, :created_at
+		)
+	`
+	if _, err := tx.NamedExec(query, movement); err != nil {
+		return nil, errors.DatabaseError(err, "Error recording stock movement")
+	}
+
+	return movement, nil
+}
+
+func (mr *MovementRepository) listMovements(column string, id uuid.UUID, limit, offset int) ([]models.StockMovement, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM stock_movements WHERE ` + column + ` = $1`
+	if err := mr.db.Get(&total, countQuery, id); err != nil {
+		return nil, 0, errors.DatabaseError(err, "Error counting stock movements")
+	}
+
+	movements := []models.StockMovement{}
+	query := `
+		SELECT * FROM stock_movements
+		WHERE ` + column + ` = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	if err := mr.db.Select(&movements, query, id, limit, offset); err != nil {
+		return nil, 0, errors.DatabaseError(err, "Error fetching stock movements")
+	}
+
+	return movements, total, nil
+}
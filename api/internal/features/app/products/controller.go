@@ -1,7 +1,16 @@
 package products
 
 import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -10,17 +19,43 @@ import (
 	"github.com/venntry/internal/shared/utils"
 	"github.com/venntry/pkg/errors"
 	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/storage"
 )
 
+const defaultSearchLimit = 20
+
+const (
+	// Bucket prefix of every image file the server stores; nothing outside it is ever deleted
+	imageKeyPrefix = "products"
+
+	maxImageSize         = 5 << 20 // 5MB
+	maxImagesPerUpload   = 10
+	imageDeletionTimeout = 30 * time.Second
+)
+
+// Accepted product image formats, keyed by sniffed content type
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
 type ProductController struct {
 	repo      IProductRepository
 	validator *ProductValidator
+	storage   storage.IStorage
+	processor *ImageProcessor
 }
 
-func NewProductController(repo IProductRepository, validator *ProductValidator) *ProductController {
+// NewProductController creates the product controller. storage and processor may be nil when
+// no bucket is configured, in which case image uploads are rejected.
+func NewProductController(repo IProductRepository, validator *ProductValidator, storage storage.IStorage, processor *ImageProcessor) *ProductController {
 	return &ProductController{
 		repo:      repo,
 		validator: validator,
+		storage:   storage,
+		processor: processor,
 	}
 }
 
@@ -30,18 +65,53 @@ func (pc *ProductController) ListProducts(ctx echo.Context) error {
 		return errors.ValidationError("Invalid inventory ID")
 	}
 
-	products, err := pc.repo.ListProducts(inventoryID)
+	var req models.ProductListRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	filter := mappers.ToProductFilter(&req, inventoryID)
+	products, err := pc.repo.ListProducts(filter)
 	if err != nil {
 		return logger.Error(ctx, "Failed to fetch products", err,
 			logger.Field("inventory_id", inventoryID),
 		)
 	}
 
-	response := make([]*models.ProductResponse, len(products))
-	for i, product := range products {
-		response[i] = mappers.ToProductResponse(&product)
+	response := mappers.ToProductPage(products, filter.Limit)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (pc *ProductController) SearchProducts(ctx echo.Context) error {
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	var req models.ProductSearchRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	term := strings.TrimSpace(req.Query)
+	if term == "" {
+		return errors.ValidationError("Search query is required")
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	hits, err := pc.repo.SearchProducts(inventoryID, term, limit)
+	if err != nil {
+		return logger.Error(ctx, "Failed to search products", err,
+			logger.Field("inventory_id", inventoryID),
+			logger.Field("query", term),
+		)
 	}
 
+	response := mappers.ToProductSearchResponse(term, hits)
 	return ctx.JSON(http.StatusOK, response)
 }
 
@@ -84,6 +154,11 @@ func (pc *ProductController) GetProductByID(ctx echo.Context) error {
 }
 
 func (pc *ProductController) CreateProduct(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
 	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
 	if err != nil {
 		return errors.ValidationError("Invalid inventory ID")
@@ -101,16 +176,12 @@ func (pc *ProductController) CreateProduct(ctx echo.Context) error {
 		return err
 	}
 
-	warehouseIDs := make([]uuid.UUID, len(req.WarehouseIDs))
-	for i, id := range req.WarehouseIDs {
-		warehouseID, err := uuid.Parse(id)
-		if err != nil {
-			return errors.ValidationError("Invalid warehouse ID")
-		}
-		warehouseIDs[i] = warehouseID
+	warehouseIDs, err := parseWarehouseIDs(req.WarehouseIDs)
+	if err != nil {
+		return err
 	}
 
-	if err := pc.repo.CreateProduct(product, req.CategoryNames, req.ImageData, warehouseIDs); err != nil {
+	if err := pc.repo.CreateProduct(product, req.CategoryNames, req.ImageData, warehouseIDs, user.Id); err != nil {
 		return logger.Error(ctx, "Failed to create product", err,
 			logger.Field("product_name", product.Name),
 		)
@@ -121,6 +192,11 @@ func (pc *ProductController) CreateProduct(ctx echo.Context) error {
 }
 
 func (pc *ProductController) UpdateProduct(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
 	productID, err := uuid.Parse(ctx.Param("productId"))
 	if err != nil {
 		return errors.ValidationError("Invalid product ID")
@@ -145,16 +221,12 @@ func (pc *ProductController) UpdateProduct(ctx echo.Context) error {
 		return err
 	}
 
-	warehouseIDs := make([]uuid.UUID, len(req.WarehouseIDs))
-	for i, id := range req.WarehouseIDs {
-		warehouseID, err := uuid.Parse(id)
-		if err != nil {
-			return errors.ValidationError("Invalid warehouse ID")
-		}
-		warehouseIDs[i] = warehouseID
+	warehouseIDs, err := parseWarehouseIDs(req.WarehouseIDs)
+	if err != nil {
+		return err
 	}
 
-	if err := pc.repo.UpdateProduct(product, req.CategoryNames, req.ImageData, warehouseIDs); err != nil {
+	if err := pc.repo.UpdateProduct(product, req.CategoryNames, req.ImageData, warehouseIDs, user.Id); err != nil {
 		return logger.Error(ctx, "Failed to update product", err,
 			logger.Field("product_id", productID),
 		)
@@ -171,17 +243,722 @@ func (pc *ProductController) UpdateProduct(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// PatchProduct applies a JSON merge patch; relations absent from the patch keep their current state
+func (pc *ProductController) PatchProduct(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	existing, err := pc.repo.GetProductWithRelations(productID)
+	if err != nil {
+		return logger.Error(ctx, "Product not found", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	req := mappers.ToProductRequest(&existing)
+	fields, err := utils.BindMergePatch(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	mappers.SanitizeProductRequest(req)
+	product := mappers.ToUpdateProduct(req, &existing)
+
+	if err := pc.validator.ValidateProductFields(product, fields); err != nil {
+		return err
+	}
+
+	warehouseIDs, err := parseWarehouseIDs(req.WarehouseIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := pc.repo.UpdateProduct(product, req.CategoryNames, req.ImageData, warehouseIDs, user.Id); err != nil {
+		return logger.Error(ctx, "Failed to patch product", err,
+			logger.Field("product_id", productID),
+			logger.Field("fields", fields),
+		)
+	}
+
+	updatedProduct, err := pc.repo.GetProductWithRelations(productID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch updated product", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	response := mappers.ToProductResponse(&updatedProduct)
+	return ctx.JSON(http.StatusOK, response)
+}
+
 func (pc *ProductController) DeleteProduct(ctx echo.Context) error {
 	productID, err := uuid.Parse(ctx.Param("productId"))
 	if err != nil {
 		return errors.ValidationError("Invalid product ID")
 	}
 
+	images, err := pc.repo.GetProductImages(productID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to delete product", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
 	if err := pc.repo.DeleteProduct(productID); err != nil {
 		return logger.Error(ctx, "Failed to delete product", err,
 			logger.Field("product_id", productID),
 		)
 	}
 
+	pc.deleteStoredImages(imageFileKeys(images...)...)
+
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+// ImportProducts loads products from a CSV or XLSX upload. Every row is validated and
+// reported; only with ?commit=true and an error-free file are the rows written.
+func (pc *ProductController) ImportProducts(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	commit, _ := strconv.ParseBool(ctx.QueryParam("commit"))
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return errors.ValidationError("Import file is required")
+	}
+
+	sheet, err := readImportSheet(file, ctx.FormValue("mapping"))
+	if err != nil {
+		return err
+	}
+
+	var skus []string
+	for _, record := range sheet.records {
+		if sku, _ := sheet.cell(record, "sku"); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+
+	existingProducts, err := pc.repo.GetProductsBySKU(inventoryID, skus)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch existing products", err,
+			logger.Field("inventory_id", inventoryID),
+		)
+	}
+
+	existing := make(map[string]models.Product, len(existingProducts))
+	for _, product := range existingProducts {
+		existing[product.SKU] = product
+	}
+
+	warehouses, err := pc.repo.GetInventoryWarehouses(inventoryID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch warehouses", err,
+			logger.Field("inventory_id", inventoryID),
+		)
+	}
+
+	report := &models.ProductImportReport{DryRun: !commit, Rows: []models.ProductImportRowResult{}}
+	rows := []models.ProductImportRow{}
+	seen := map[string]map[string]int{"sku": {}, "name": {}, "code": {}}
+
+	for i, record := range sheet.records {
+		if isBlank(record) {
+			continue
+		}
+
+		// Row numbers match the spreadsheet, where the header is row 1
+		row, result, err := pc.prepareImportRow(ctx, sheet, record, i+2, inventoryID, existing, warehouses, seen)
+		if err != nil {
+			return logger.Error(ctx, "Failed to validate import row", err,
+				logger.Field("inventory_id", inventoryID),
+				logger.Field("row", i+2),
+			)
+		}
+
+		report.TotalRows++
+		report.Rows = append(report.Rows, *result)
+		if len(result.Errors) > 0 {
+			continue
+		}
+
+		report.ValidRows++
+		if row.Existing {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+		rows = append(rows, *row)
+	}
+
+	if !commit {
+		return ctx.JSON(http.StatusOK, report)
+	}
+
+	if report.ValidRows != report.TotalRows {
+		return ctx.JSON(http.StatusUnprocessableEntity, report)
+	}
+
+	if err := pc.repo.ImportProducts(rows, user.Id); err != nil {
+		return logger.Error(ctx, "Failed to import products", err,
+			logger.Field("inventory_id", inventoryID),
+			logger.Field("rows", len(rows)),
+		)
+	}
+
+	report.Committed = true
+	return ctx.JSON(http.StatusOK, report)
+}
+
+func (pc *ProductController) GetProductStock(ctx echo.Context) error {
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	return pc.respondWithStock(ctx, productID)
+}
+
+func (pc *ProductController) SetWarehouseStock(ctx echo.Context) error {
+	var req models.StockRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	if req.Reason == "" {
+		req.Reason = models.ReasonCountCorrection
+	}
+
+	return pc.changeWarehouseStock(ctx, 0, req.Reason, req.Reference, func(change *models.StockChange) error {
+		_, err := pc.repo.UpdateProductWarehouseQuantity(change, req.Quantity)
+		return err
+	})
+}
+
+func (pc *ProductController) IncrementWarehouseStock(ctx echo.Context) error {
+	var req models.StockAdjustmentRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	if req.Reason == "" {
+		req.Reason = models.ReasonReceipt
+	}
+
+	return pc.changeWarehouseStock(ctx, req.Quantity, req.Reason, req.Reference, func(change *models.StockChange) error {
+		_, err := pc.repo.AdjustProductWarehouseQuantity(change)
+		return err
+	})
+}
+
+func (pc *ProductController) DecrementWarehouseStock(ctx echo.Context) error {
+	var req models.StockAdjustmentRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	if req.Reason == "" {
+		req.Reason = models.ReasonSale
+	}
+
+	return pc.changeWarehouseStock(ctx, -req.Quantity, req.Reason, req.Reference, func(change *models.StockChange) error {
+		_, err := pc.repo.AdjustProductWarehouseQuantity(change)
+		return err
+	})
+}
+
+// UploadProductImages stores the files of the multipart "images" field and appends them to
+// the product's images
+func (pc *ProductController) UploadProductImages(ctx echo.Context) error {
+	if pc.storage == nil {
+		return errors.New(errors.InternalErr, "File storage is not configured", http.StatusServiceUnavailable)
+	}
+
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return errors.ValidationError("Request must be a multipart form")
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		return errors.ValidationError("At least one file is required in the 'images' form field")
+	}
+	if len(files) > maxImagesPerUpload {
+		return errors.ValidationError(fmt.Sprintf("At most %d images can be uploaded at once", maxImagesPerUpload))
+	}
+
+	// Check every file before storing any of them
+	for _, file := range files {
+		if file.Size > maxImageSize {
+			return errors.ValidationError(fmt.Sprintf("Image %q must be 5MB or smaller", file.Filename))
+		}
+	}
+
+	images := make([]models.Image, 0, len(files))
+	var uploaded []string
+	for _, file := range files {
+		image, err := pc.storeImage(ctx, file)
+		if err != nil {
+			pc.deleteStoredImages(uploaded...)
+			return logger.Error(ctx, "Failed to upload product image", err,
+				logger.Field("product_id", productID),
+				logger.Field("filename", file.Filename),
+			)
+		}
+
+		images = append(images, *image)
+		uploaded = append(uploaded, image.FileKey)
+	}
+
+	stored, err := pc.repo.AddProductImages(productID, images)
+	if err != nil {
+		pc.deleteStoredImages(uploaded...)
+		return logger.Error(ctx, "Failed to save product images", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	pc.processImages(stored)
+
+	return ctx.JSON(http.StatusCreated, toImageResponses(stored))
+}
+
+// CreateImageUpload signs a URL the client can PUT an image to directly, bypassing the API.
+// The image is added to the product once the upload is confirmed.
+func (pc *ProductController) CreateImageUpload(ctx echo.Context) error {
+	if pc.storage == nil {
+		return errors.New(errors.InternalErr, "File storage is not configured", http.StatusServiceUnavailable)
+	}
+
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	var req models.ImageUploadRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	upload := &models.ImageUpload{
+		ID:          uuid.New(),
+		ProductId:   productID,
+		UserId:      user.Id,
+		FileKey:     storage.NewFileKey(imageKeyPrefix, "image"+imageExtensions[req.ContentType]),
+		ContentType: req.ContentType,
+		SizeBytes:   req.Size,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(uploadConfirmTTL),
+	}
+
+	signed, err := pc.storage.SignedUploadURL(ctx.Request().Context(), upload.FileKey, upload.ContentType, upload.SizeBytes, uploadURLTTL)
+	if err != nil {
+		return logger.Error(ctx, "Failed to sign image upload", errors.InternalError(err, "Error signing upload"),
+			logger.Field("product_id", productID),
+		)
+	}
+
+	if err := pc.repo.CreateImageUpload(upload); err != nil {
+		return logger.Error(ctx, "Failed to create image upload", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	return ctx.JSON(http.StatusCreated, models.ImageUploadResponse{
+		UploadID:  upload.ID,
+		URL:       signed.URL,
+		Method:    signed.Method,
+		Headers:   signed.Headers,
+		FileKey:   upload.FileKey,
+		ExpiresAt: signed.ExpiresAt,
+	})
+}
+
+// ConfirmImageUpload checks that the client's direct upload reached the bucket as signed and
+// appends it to the product's images
+func (pc *ProductController) ConfirmImageUpload(ctx echo.Context) error {
+	if pc.storage == nil {
+		return errors.New(errors.InternalErr, "File storage is not configured", http.StatusServiceUnavailable)
+	}
+
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	uploadID, err := uuid.Parse(ctx.Param("uploadId"))
+	if err != nil {
+		return errors.ValidationError("Invalid upload ID")
+	}
+
+	upload, err := pc.repo.GetImageUpload(productID, uploadID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch image upload", err,
+			logger.Field("product_id", productID),
+			logger.Field("upload_id", uploadID),
+		)
+	}
+
+	info, err := pc.storage.Head(ctx.Request().Context(), upload.FileKey)
+	if stderrors.Is(err, storage.ErrNotFound) {
+		return errors.ValidationError("The file has not been uploaded yet")
+	} else if err != nil {
+		return logger.Error(ctx, "Failed to check uploaded image", errors.InternalError(err, "Error checking upload"),
+			logger.Field("file_key", upload.FileKey),
+		)
+	}
+	if info.Size != upload.SizeBytes || (info.ContentType != "" && info.ContentType != upload.ContentType) {
+		return errors.ValidationError("The uploaded file does not match the requested size and type")
+	}
+
+	image := models.Image{
+		URL:       pc.storage.URL(upload.FileKey),
+		FileKey:   upload.FileKey,
+		SizeBytes: info.Size,
+	}
+	images, err := pc.repo.ConfirmImageUpload(productID, uploadID, image)
+	if err != nil {
+		return logger.Error(ctx, "Failed to confirm image upload", err,
+			logger.Field("product_id", productID),
+			logger.Field("upload_id", uploadID),
+		)
+	}
+
+	pc.processImages(images)
+
+	return ctx.JSON(http.StatusCreated, toImageResponses(images))
+}
+
+func (pc *ProductController) ReorderProductImages(ctx echo.Context) error {
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	var req models.ImageOrderRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	imageIDs := make([]uuid.UUID, len(req.ImageIDs))
+	for i, id := range req.ImageIDs {
+		imageIDs[i] = uuid.MustParse(id)
+	}
+
+	images, err := pc.repo.ReorderProductImages(productID, imageIDs)
+	if err != nil {
+		return logger.Error(ctx, "Failed to reorder product images", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, toImageResponses(images))
+}
+
+func (pc *ProductController) SetPrimaryProductImage(ctx echo.Context) error {
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	imageID, err := uuid.Parse(ctx.Param("imageId"))
+	if err != nil {
+		return errors.ValidationError("Invalid image ID")
+	}
+
+	images, err := pc.repo.SetPrimaryProductImage(productID, imageID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to set primary image", err,
+			logger.Field("product_id", productID),
+			logger.Field("image_id", imageID),
+		)
+	}
+
+	return ctx.JSON(http.StatusOK, toImageResponses(images))
+}
+
+func (pc *ProductController) DeleteProductImage(ctx echo.Context) error {
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	imageID, err := uuid.Parse(ctx.Param("imageId"))
+	if err != nil {
+		return errors.ValidationError("Invalid image ID")
+	}
+
+	image, err := pc.repo.DeleteProductImage(productID, imageID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to delete product image", err,
+			logger.Field("product_id", productID),
+			logger.Field("image_id", imageID),
+		)
+	}
+
+	pc.deleteStoredImages(imageFileKeys(*image)...)
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Helper methods
+func (pc *ProductController) changeWarehouseStock(ctx echo.Context, delta int, reason string, reference *string, write func(*models.StockChange) error) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	productID, err := uuid.Parse(ctx.Param("productId"))
+	if err != nil {
+		return errors.ValidationError("Invalid product ID")
+	}
+
+	warehouseID, err := uuid.Parse(ctx.Param("warehouseId"))
+	if err != nil {
+		return errors.ValidationError("Invalid warehouse ID")
+	}
+
+	change := mappers.ToStockChange(productID, warehouseID, user.Id, delta, reason, reference)
+	if err := write(change); err != nil {
+		return logger.Error(ctx, "Failed to update warehouse stock", err,
+			logger.Field("product_id", productID),
+			logger.Field("warehouse_id", warehouseID),
+		)
+	}
+
+	return pc.respondWithStock(ctx, productID)
+}
+
+func (pc *ProductController) respondWithStock(ctx echo.Context, productID uuid.UUID) error {
+	product, err := pc.repo.GetProduct(productID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch product", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	stock, err := pc.repo.GetProductStock(productID)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch product stock", err,
+			logger.Field("product_id", productID),
+		)
+	}
+
+	response := mappers.ToProductStockResponse(&product, stock)
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func parseWarehouseIDs(ids []string) ([]uuid.UUID, error) {
+	warehouseIDs := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		warehouseID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errors.ValidationError("Invalid warehouse ID")
+		}
+		warehouseIDs[i] = warehouseID
+	}
+	return warehouseIDs, nil
+}
+
+// prepareImportRow builds and validates the product for one spreadsheet row. Validation
+// problems are collected in the row result; only unexpected failures are returned as errors.
+func (pc *ProductController) prepareImportRow(ctx echo.Context, sheet *importSheet, record []string, rowNumber int, inventoryID uuid.UUID, existing map[string]models.Product, warehouses []models.Warehouse, seen map[string]map[string]int) (*models.ProductImportRow, *models.ProductImportRowResult, error) {
+	sku, _ := sheet.cell(record, "sku")
+	stored, found := existing[sku]
+
+	req := &models.ProductRequest{}
+	if found {
+		req = mappers.ToProductRequest(&stored)
+	}
+
+	warehouseRefs, problems := sheet.applyImportRecord(record, req)
+	if err := ctx.Validate(req); err != nil {
+		problems = append(problems, describeValidation(err)...)
+	}
+
+	mappers.SanitizeProductRequest(req)
+
+	var product *models.Product
+	if found {
+		product = mappers.ToUpdateProduct(req, &stored)
+	} else {
+		product = mappers.ToCreateProduct(req, inventoryID)
+	}
+
+	warehouseIDs, warehouseProblems := resolveImportWarehouses(warehouseRefs, warehouses)
+	problems = append(problems, warehouseProblems...)
+
+	if len(problems) == 0 {
+		if err := pc.validator.ValidateProduct(product); err != nil {
+			appErr, ok := err.(*errors.AppError)
+			if !ok || appErr.Type != errors.ValidationErr {
+				return nil, nil, err
+			}
+			problems = append(problems, strings.Split(appErr.Message, "; ")...)
+		}
+	}
+
+	// Rows in the same file must not collide with each other either
+	unique := map[string]string{"sku": product.SKU, "name": product.Name}
+	if product.Code != nil {
+		unique["code"] = *product.Code
+	}
+	for field, value := range unique {
+		if value == "" {
+			continue
+		}
+		if previous, ok := seen[field][value]; ok {
+			problems = append(problems, fmt.Sprintf("Duplicate %s %q (also on row %d)", field, value, previous))
+			continue
+		}
+		seen[field][value] = rowNumber
+	}
+
+	action := models.ImportActionCreate
+	if found {
+		action = models.ImportActionUpdate
+	}
+
+	result := &models.ProductImportRowResult{
+		Row:    rowNumber,
+		SKU:    product.SKU,
+		Name:   product.Name,
+		Action: action,
+		Errors: problems,
+	}
+
+	row := &models.ProductImportRow{
+		Row:           rowNumber,
+		Product:       product,
+		Existing:      found,
+		CategoryNames: req.CategoryNames,
+		WarehouseIDs:  warehouseIDs,
+	}
+
+	return row, result, nil
+}
+
+// storeImage checks the sniffed type of an uploaded file and puts it in the bucket
+func (pc *ProductController) storeImage(ctx echo.Context, file *multipart.FileHeader) (*models.Image, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, errors.InternalError(err, "Failed to read uploaded file")
+	}
+	defer src.Close()
+
+	// Trust the file contents rather than the client-supplied content type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, errors.ValidationError(fmt.Sprintf("Unable to read image %q", file.Filename))
+	}
+	head = head[:n]
+
+	ext, ok := imageExtensions[http.DetectContentType(head)]
+	if !ok {
+		return nil, errors.ValidationError(fmt.Sprintf("Image %q must be a JPEG, PNG, GIF or WebP file", file.Filename))
+	}
+
+	result, err := pc.storage.Upload(ctx.Request().Context(), io.MultiReader(bytes.NewReader(head), src), imageKeyPrefix, "image"+ext)
+	if err != nil {
+		return nil, errors.InternalError(err, "Error uploading image")
+	}
+
+	return &models.Image{URL: result.URL, FileKey: result.FileKey, SizeBytes: file.Size}, nil
+}
+
+// deleteStoredImages removes objects in the background; a failure leaves an orphaned object
+// behind but must not fail the request. Keys outside the image prefix are never touched.
+func (pc *ProductController) deleteStoredImages(fileKeys ...string) {
+	if pc.storage == nil {
+		return
+	}
+
+	var owned []string
+	for _, key := range fileKeys {
+		if isImageKey(key) {
+			owned = append(owned, key)
+		}
+	}
+	if len(owned) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), imageDeletionTimeout)
+		defer cancel()
+
+		for _, key := range owned {
+			if err := pc.storage.Delete(ctx, key); err != nil {
+				logger.LogError("Error deleting product image from storage", err, logger.Field("file_key", key))
+			}
+		}
+	}()
+}
+
+// processImages queues the images that still need their variants
+func (pc *ProductController) processImages(images []models.Image) {
+	if pc.processor == nil {
+		return
+	}
+
+	for _, img := range images {
+		if img.Status == models.ImagePending {
+			pc.processor.Enqueue(img.ID)
+		}
+	}
+}
+
+// imageFileKeys lists the objects the server stored for images, variants included. Images
+// that are not managed refer to keys the server did not issue and are skipped.
+func imageFileKeys(images ...models.Image) []string {
+	var keys []string
+	for _, img := range images {
+		if !img.Managed {
+			continue
+		}
+		keys = append(keys, img.FileKey)
+		for _, variant := range img.Variants {
+			keys = append(keys, variant.FileKey)
+		}
+	}
+	return keys
+}
+
+func isImageKey(key string) bool {
+	return strings.HasPrefix(key, imageKeyPrefix+"/")
+}
+
+func toImageResponses(images []models.Image) []models.ImageResponse {
+	response := make([]models.ImageResponse, len(images))
+	for i, img := range images {
+		response[i] = *mappers.ToImageResponse(&img)
+	}
+	return response
+}
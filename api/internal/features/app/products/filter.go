@@ -0,0 +1,157 @@
+package products
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+)
+
+// sortColumn maps a public sort key to its SQL expression and the type its cursor value is cast to
+type sortColumn struct {
+	expr     string
+	castType string
+	value    func(p *models.Product) string
+}
+
+var productSortColumns = map[string]sortColumn{
+	models.ProductSortName: {"p.name", "text", func(p *models.Product) string { return p.Name }},
+	models.ProductSortSKU:  {"COALESCE(p.sku, '')", "text", func(p *models.Product) string { return p.SKU }},
+	models.ProductSortQuantity: {"p.quantity", "integer", func(p *models.Product) string {
+		return strconv.Itoa(p.Quantity)
+	}},
+	models.ProductSortPrice: {"p.price", "integer", func(p *models.Product) string {
+		return strconv.Itoa(p.Price)
+	}},
+	models.ProductSortCost: {"p.cost", "integer", func(p *models.Product) string {
+		return strconv.Itoa(p.Cost)
+	}},
+	models.ProductSortCreatedAt: {"p.created_at", "timestamptz", func(p *models.Product) string {
+		return p.CreatedAt.Format(time.RFC3339Nano)
+	}},
+	models.ProductSortUpdatedAt: {"p.updated_at", "timestamptz", func(p *models.Product) string {
+		return p.UpdatedAt.Format(time.RFC3339Nano)
+	}},
+}
+
+// productCursor is the keyset position of the last product on a page
+type productCursor struct {
+	Sort  string    `json:"s"`
+	Order string    `json:"o"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// productQuery accumulates WHERE conditions and their positional arguments
+type productQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *productQuery) where(condition string, args ...interface{}) {
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(q.args)), 1)
+	}
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *productQuery) clause() string {
+	return strings.Join(q.conditions, " AND ")
+}
+
+// buildProductFilter translates the list filter into conditions shared by the page and count queries
+func buildProductFilter(filter *models.ProductFilter) *productQuery {
+	q := &productQuery{}
+	q.where("p.inventory_id = ?", filter.InventoryID)
+
+	if filter.CategoryID != nil {
+		q.where(`EXISTS (
+			SELECT 1 FROM product_category_map pcm
+			WHERE pcm.product_id = p.id AND pcm.category_id = ?
+		)`, *filter.CategoryID)
+	}
+
+	if filter.WarehouseID != nil {
+		q.where(`EXISTS (
+			SELECT 1 FROM warehouse_product_map wpm
+			WHERE wpm.product_id = p.id AND wpm.warehouse_id = ?
+		)`, *filter.WarehouseID)
+	}
+
+	if filter.Brand != "" {
+		q.where("LOWER(p.brand) = LOWER(?)", filter.Brand)
+	}
+
+	if filter.MinPrice != nil {
+		q.where("p.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		q.where("p.price <= ?", *filter.MaxPrice)
+	}
+	if filter.MinCost != nil {
+		q.where("p.cost >= ?", *filter.MinCost)
+	}
+	if filter.MaxCost != nil {
+		q.where("p.cost <= ?", *filter.MaxCost)
+	}
+
+	switch filter.Stock {
+	case models.StockStatusOut:
+		q.where("p.quantity = 0")
+	case models.StockStatusLow:
+		q.where("p.quantity > 0 AND p.quantity <= p.restock_level")
+	case models.StockStatusOverstocked:
+		q.where("p.quantity > p.optimal_level")
+	}
+
+	return q
+}
+
+// ProductFilterClause returns the WHERE clause and arguments matching filter against the
+// products table aliased as p. Cursor and sort are ignored. Used by features such as exports.
+func ProductFilterClause(filter *models.ProductFilter) (string, []interface{}) {
+	q := buildProductFilter(filter)
+	return q.clause(), q.args
+}
+
+// seekAfter restricts the query to rows after the cursor position in the requested sort order
+func (q *productQuery) seekAfter(filter *models.ProductFilter, column sortColumn) error {
+	if filter.Cursor == "" {
+		return nil
+	}
+
+	var cursor productCursor
+	if err := utils.DecodeCursor(filter.Cursor, &cursor); err != nil {
+		return errors.ValidationError("Invalid cursor")
+	}
+	if cursor.Sort != filter.Sort || cursor.Order != filter.Order {
+		return errors.ValidationError("Cursor does not match the requested sort order")
+	}
+
+	comparison := ">"
+	if filter.Order == "desc" {
+		comparison = "<"
+	}
+
+	q.where(fmt.Sprintf("(%s, p.id) %s (?::%s, ?)", column.expr, comparison, column.castType), cursor.Value, cursor.ID)
+	return nil
+}
+
+func nextProductCursor(filter *models.ProductFilter, column sortColumn, last *models.Product) (*string, error) {
+	cursor, err := utils.EncodeCursor(productCursor{
+		Sort:  filter.Sort,
+		Order: filter.Order,
+		Value: column.value(last),
+		ID:    last.ID,
+	})
+	if err != nil {
+		return nil, errors.InternalError(err, "Error encoding cursor")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,63 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+)
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	filter := &models.ProductFilter{InventoryID: uuid.New(), Sort: models.ProductSortUpdatedAt, Order: "desc"}
+	column := productSortColumns[filter.Sort]
+	last := &models.Product{ID: uuid.New(), UpdatedAt: time.Date(2025, 3, 1, 12, 30, 0, 123456789, time.UTC)}
+
+	cursor, err := nextProductCursor(filter, column, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter.Cursor = *cursor
+	q := buildProductFilter(filter)
+	if err := q.seekAfter(filter, column); err != nil {
+		t.Fatal(err)
+	}
+
+	wantClause := "p.inventory_id = $1 AND (p.updated_at, p.id) < ($2::timestamptz, $3)"
+	if q.clause() != wantClause {
+		t.Errorf("clause = %q, want %q", q.clause(), wantClause)
+	}
+	if q.args[1] != "2025-03-01T12:30:00.123456789Z" || q.args[2] != last.ID {
+		t.Errorf("seek arguments = %v, want the last product's position", q.args[1:])
+	}
+}
+
+func TestSeekAfterRejectsForeignCursors(t *testing.T) {
+	byName := &models.ProductFilter{Sort: models.ProductSortName, Order: "asc"}
+	cursor, err := nextProductCursor(byName, productSortColumns[byName.Sort], &models.Product{ID: uuid.New(), Name: "Widget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter models.ProductFilter
+	}{
+		{"garbage", models.ProductFilter{Sort: models.ProductSortName, Order: "asc", Cursor: "not a cursor"}},
+		{"other sort", models.ProductFilter{Sort: models.ProductSortPrice, Order: "asc", Cursor: *cursor}},
+		{"other order", models.ProductFilter{Sort: models.ProductSortName, Order: "desc", Cursor: *cursor}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := &productQuery{}
+			if err := q.seekAfter(&tc.filter, productSortColumns[tc.filter.Sort]); err == nil {
+				t.Error("expected the cursor to be rejected")
+			}
+			if len(q.conditions) != 0 {
+				t.Errorf("no condition may be added for a rejected cursor, got %v", q.conditions)
+			}
+		})
+	}
+}
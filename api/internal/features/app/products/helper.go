@@ -13,7 +13,7 @@ import (
 // Helper methods
 func (pr *ProductRepository) getProductImages(productID uuid.UUID) ([]models.Image, error) {
 	var images []models.Image
-	query := `SELECT * FROM product_images WHERE product_id = $1 ORDER BY is_primary DESC, created_at ASC`
+	query := `SELECT * FROM product_images WHERE product_id = $1 ORDER BY position ASC, created_at ASC`
 
 	err := pr.db.Select(&images, query, productID)
 	if err != nil {
@@ -53,112 +53,357 @@ func (pr *ProductRepository) getProductWarehouses(productId uuid.UUID) ([]models
 	return warehouses, nil
 }
 
-func (pr *ProductRepository) createProductImages(tx *sqlx.Tx, productID uuid.UUID, images []models.ImageRequest) error {
-	for i, img := range images {
-		query := `
-			INSERT INTO product_images (
-				id, url, file_key, product_id, is_primary, created_at, updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`
-		_, err := tx.Exec(query,
-			uuid.New(),
-			img.URL,
-			img.FileKey,
-			productID,
-			i == 0, // First image is primary
-			time.Now(),
-			time.Now(),
-		)
+// checkNewProductImages guards product creation: a new product has no images to refer to yet
+func checkNewProductImages(images []models.ImageRequest) error {
+	if len(images) > 0 {
+		return errors.ValidationError("Images are added through the image upload endpoints once the product exists")
+	}
+	return nil
+}
+
+// reconcileProductImages applies the order and primary flag of the requested images, which
+// must belong to the product, and removes the images left out
+func (pr *ProductRepository) reconcileProductImages(tx *sqlx.Tx, productID uuid.UUID, images []models.ImageRequest) error {
+	existing, err := pr.getProductImagesTx(tx, productID)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]models.Image, len(existing))
+	var currentPrimary uuid.UUID
+	for _, img := range existing {
+		byID[img.ID] = img
+		if img.IsPrimary {
+			currentPrimary = img.ID
+		}
+	}
+
+	requested := make([]uuid.UUID, 0, len(images))
+	seen := make(map[uuid.UUID]bool, len(images))
+	for _, img := range images {
+		id, err := uuid.Parse(img.ID)
+		if err != nil {
+			return errors.ValidationError("Invalid image ID")
+		}
+		if _, ok := byID[id]; !ok {
+			return errors.ValidationError("Image " + img.ID + " does not belong to this product")
+		}
+		if !seen[id] {
+			seen[id] = true
+			requested = append(requested, id)
+		}
+	}
 
+	primary := primaryImageIndex(images, requested, currentPrimary)
+
+	for i, id := range requested {
+		stored := byID[id]
+		delete(byID, id)
+		if stored.IsPrimary == (i == primary) && stored.Position == i {
+			continue
+		}
+
+		_, err := tx.Exec(`
+			UPDATE product_images SET is_primary = $1, position = $2, updated_at = $3
+			WHERE id = $4
+		`, i == primary, i, time.Now(), id)
 		if err != nil {
-			return errors.DatabaseError(err, "Error creating product image")
+			return errors.DatabaseError(err, "Error updating product image")
 		}
 	}
+
+	// Whatever is left was dropped from the request
+	for _, stored := range byID {
+		if _, err := tx.Exec("DELETE FROM product_images WHERE id = $1", stored.ID); err != nil {
+			return errors.DatabaseError(err, "Error removing product image")
+		}
+	}
+
 	return nil
 }
 
+func (pr *ProductRepository) getProductImagesTx(tx *sqlx.Tx, productID uuid.UUID) ([]models.Image, error) {
+	var images []models.Image
+	query := `SELECT * FROM product_images WHERE product_id = $1`
+
+	if err := tx.Select(&images, query, productID); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching product images")
+	}
+
+	return images, nil
+}
+
 func (pr *ProductRepository) linkProductCategories(tx *sqlx.Tx, productID, inventoryID uuid.UUID, categoryNames []string) error {
 	for _, name := range categoryNames {
-		// Find or create category
-		var categoryID uuid.UUID
-
-		err := tx.Get(&categoryID, "SELECT id FROM product_categories WHERE name = $1 AND inventory_id = $2", name, inventoryID)
-		if err == sql.ErrNoRows {
-			categoryID = uuid.New()
-			_, err = tx.Exec(`
-				INSERT INTO product_categories (id, name, inventory_id, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5)
-			`, categoryID, name, inventoryID, time.Now(), time.Now())
-			if err != nil {
-				return errors.DatabaseError(err, "Error creating category")
-			}
-		} else if err != nil {
-			return errors.DatabaseError(err, "Error finding category")
+		categoryID, err := pr.findOrCreateCategory(tx, name, inventoryID)
+		if err != nil {
+			return err
+		}
+
+		if err := pr.linkProductCategory(tx, productID, categoryID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileProductCategories links newly named categories and unlinks ones no longer requested
+func (pr *ProductRepository) reconcileProductCategories(tx *sqlx.Tx, productID, inventoryID uuid.UUID, categoryNames []string) error {
+	var linked []uuid.UUID
+	if err := tx.Select(&linked, "SELECT category_id FROM product_category_map WHERE product_id = $1", productID); err != nil {
+		return errors.DatabaseError(err, "Error fetching product categories")
+	}
+
+	stale := make(map[uuid.UUID]bool, len(linked))
+	for _, id := range linked {
+		stale[id] = true
+	}
+
+	for _, name := range categoryNames {
+		categoryID, err := pr.findOrCreateCategory(tx, name, inventoryID)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := stale[categoryID]; ok {
+			stale[categoryID] = false
+			continue
 		}
 
-		// Link to product
+		if err := pr.linkProductCategory(tx, productID, categoryID); err != nil {
+			return err
+		}
+	}
+
+	for categoryID, remove := range stale {
+		if !remove {
+			continue
+		}
+
+		_, err := tx.Exec("DELETE FROM product_category_map WHERE product_id = $1 AND category_id = $2", productID, categoryID)
+		if err != nil {
+			return errors.DatabaseError(err, "Error unlinking product category")
+		}
+	}
+
+	return nil
+}
+
+func (pr *ProductRepository) findOrCreateCategory(tx *sqlx.Tx, name string, inventoryID uuid.UUID) (uuid.UUID, error) {
+	var categoryID uuid.UUID
+
+	err := tx.Get(&categoryID, "SELECT id FROM product_categories WHERE name = $1 AND inventory_id = $2", name, inventoryID)
+	if err == sql.ErrNoRows {
+		categoryID = uuid.New()
 		_, err = tx.Exec(`
-			INSERT INTO product_category_map (product_id, category_id, created_at)
-			VALUES ($1, $2, $3)
-		`, productID, categoryID, time.Now())
+			INSERT INTO product_categories (id, name, inventory_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, categoryID, name, inventoryID, time.Now(), time.Now())
 		if err != nil {
-			return errors.DatabaseError(err, "Error linking product to category")
+			return uuid.Nil, errors.DatabaseError(err, "Error creating category")
 		}
+	} else if err != nil {
+		return uuid.Nil, errors.DatabaseError(err, "Error finding category")
 	}
+
+	return categoryID, nil
+}
+
+func (pr *ProductRepository) linkProductCategory(tx *sqlx.Tx, productID, categoryID uuid.UUID) error {
+	_, err := tx.Exec(`
+		INSERT INTO product_category_map (product_id, category_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (product_id, category_id) DO NOTHING
+	`, productID, categoryID, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error linking product to category")
+	}
+
 	return nil
 }
 
-func (pr *ProductRepository) linkProductWarehouses(tx *sqlx.Tx, productID uuid.UUID, warehouseIDs []uuid.UUID) error {
+func (pr *ProductRepository) linkProductWarehouses(tx *sqlx.Tx, productID uuid.UUID, warehouseIDs []uuid.UUID, userID uuid.UUID) error {
 	for _, warehouseID := range warehouseIDs {
-		_, err := tx.Exec(`
-            INSERT INTO warehouse_product_map (product_id, warehouse_id, quantity, created_at)
-            VALUES ($1, $2, $3, $4)
-        `, productID, warehouseID, 0, time.Now())
-		if err != nil {
-			return errors.DatabaseError(err, "Error linking product to warehouse")
+		if err := pr.ledger.LinkWarehouse(tx, productID, warehouseID, &userID); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func (pr *ProductRepository) clearProductRelations(tx *sqlx.Tx, productID uuid.UUID) error {
-	_, err := tx.Exec("DELETE FROM product_images WHERE product_id = $1", productID)
+// reconcileProductWarehouses keeps existing warehouse links and their quantities, links new
+// warehouses and unlinks empty ones. Warehouses still holding stock cannot be unlinked.
+func (pr *ProductRepository) reconcileProductWarehouses(tx *sqlx.Tx, productID uuid.UUID, warehouseIDs []uuid.UUID, userID uuid.UUID) error {
+	var linked []models.WarehouseProduct
+	if err := tx.Select(&linked, "SELECT * FROM warehouse_product_map WHERE product_id = $1", productID); err != nil {
+		return errors.DatabaseError(err, "Error fetching product warehouses")
+	}
+
+	requested := make(map[uuid.UUID]bool, len(warehouseIDs))
+	for _, id := range warehouseIDs {
+		requested[id] = true
+	}
+
+	for _, link := range linked {
+		if requested[link.WarehouseID] {
+			continue
+		}
+		if link.Quantity > 0 {
+			return errors.ValidationError("Cannot remove a warehouse that still holds stock; transfer or adjust it to zero first")
+		}
+
+		_, err := tx.Exec("DELETE FROM warehouse_product_map WHERE product_id = $1 AND warehouse_id = $2", productID, link.WarehouseID)
+		if err != nil {
+			return errors.DatabaseError(err, "Error unlinking product warehouse")
+		}
+	}
+
+	return pr.linkProductWarehouses(tx, productID, warehouseIDs, userID)
+}
+
+// primaryImageIndex picks, among the deduplicated requested IDs, the image flagged primary in
+// the request, then the image that is already primary, then the first image.
+func primaryImageIndex(images []models.ImageRequest, requested []uuid.UUID, currentPrimary uuid.UUID) int {
+	for _, img := range images {
+		if !img.IsPrimary {
+			continue
+		}
+		primary, _ := uuid.Parse(img.ID)
+		for i, id := range requested {
+			if id == primary {
+				return i
+			}
+		}
+	}
+
+	for i, id := range requested {
+		if id == currentPrimary {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// insertProduct creates a product with its opening stock and relations inside tx
+func (pr *ProductRepository) insertProduct(tx *sqlx.Tx, product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIDs []uuid.UUID, userID uuid.UUID) error {
+	query := `
+		INSERT INTO products (
+			id, name, sku, code, brand, model, description, quantity,
+			restock_level, optimal_level, cost, price, inventory_id,
+			created_at, updated_at
+		) VALUES (
+			:id, :name, :sku, :code, :brand, :model, :description, :quantity,
+			:restock_level, :optimal_level, :cost, :price, :inventory_id,
+			:created_at, :updated_at
+		)
+	`
+	// Opening stock is booked through the ledger once the product row exists
+	openingStock := product.Quantity
+	product.Quantity = 0
+
+	_, err := tx.NamedExec(query, product)
 	if err != nil {
-		return errors.DatabaseError(err, "Error clearing product images")
+		return errors.DatabaseError(err, "Error creating product")
+	}
+
+	if err := pr.setProductStock(tx, product, openingStock, userID); err != nil {
+		return err
+	}
+
+	// Handle product images
+	if err := checkNewProductImages(images); err != nil {
+		return err
+	}
+
+	// Handle product categories
+	if err := pr.linkProductCategories(tx, product.ID, product.InventoryID, categoryNames); err != nil {
+		return err
 	}
 
-	_, err = tx.Exec("DELETE FROM product_category_map WHERE product_id = $1", productID)
+	// Handle product warehouses
+	return pr.linkProductWarehouses(tx, product.ID, warehouseIDs, userID)
+}
+
+// updateProductRow writes the scalar product fields and books any quantity change
+func (pr *ProductRepository) updateProductRow(tx *sqlx.Tx, product *models.Product, userID uuid.UUID) error {
+	query := `
+		UPDATE products SET
+			name = :name, sku = :sku, code = :code, brand = :brand,
+			model = :model, description = :description,
+			restock_level = :restock_level, optimal_level = :optimal_level,
+			cost = :cost, price = :price, updated_at = :updated_at
+		WHERE id = :id
+	`
+	_, err := tx.NamedExec(query, product)
 	if err != nil {
-		return errors.DatabaseError(err, "Error clearing product categories")
+		return errors.DatabaseError(err, "Error updating product")
 	}
 
-	_, err = tx.Exec("DELETE FROM warehouse_product_map WHERE product_id = $1", productID)
+	return pr.setProductStock(tx, product, product.Quantity, userID)
+}
+
+// setProductStock books the difference between the stored and requested product quantity as an
+// adjustment. Warehouse-stocked products keep the total derived from their warehouses instead.
+func (pr *ProductRepository) setProductStock(tx *sqlx.Tx, product *models.Product, quantity int, userID uuid.UUID) error {
+	stocked, err := pr.ledger.IsWarehouseStocked(tx, product.ID)
 	if err != nil {
-		return errors.DatabaseError(err, "Error clearing product warehouses")
+		return err
 	}
 
+	if stocked {
+		if err := tx.Get(&product.Quantity, `SELECT quantity FROM products WHERE id = $1`, product.ID); err != nil {
+			return errors.DatabaseError(err, "Error fetching product quantity")
+		}
+		return nil
+	}
+
+	change := &models.StockChange{
+		ProductID: product.ID,
+		Reason:    models.ReasonAdjustment,
+		UserID:    &userID,
+	}
+	if _, err := pr.ledger.SetStock(tx, change, quantity); err != nil {
+		return err
+	}
+
+	product.Quantity = quantity
 	return nil
 }
 
-func (pr *ProductRepository) UpdateProductWarehouseQuantity(productID, warehouseID uuid.UUID, quantity int) error {
-	_, err := pr.db.Exec(`
-        UPDATE warehouse_product_map 
-        SET quantity = $1 
-        WHERE product_id = $2 AND warehouse_id = $3
-    `, quantity, productID, warehouseID)
+// changeWarehouseStock runs a ledger write for one warehouse, linking the product to it first
+func (pr *ProductRepository) changeWarehouseStock(change *models.StockChange, write func(tx *sqlx.Tx) (*models.StockMovement, error)) (*models.StockMovement, error) {
+	product, err := pr.GetProduct(change.ProductID)
+	if err != nil {
+		return nil, err
+	}
 
+	tx, err := pr.db.Beginx()
 	if err != nil {
-		return errors.DatabaseError(err, "Error updating product quantity in warehouse")
+		return nil, errors.DatabaseError(err, "Error starting transaction")
 	}
+	defer tx.Rollback()
 
-	// Invalidate relevant caches
-	pr.cache.Delete(productCacheKey(productID))
-	return nil
+	if err := pr.ledger.LinkWarehouse(tx, change.ProductID, *change.WarehouseID, change.UserID); err != nil {
+		return nil, err
+	}
+
+	movement, err := write(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(product.ID, product.InventoryID)
+	return movement, nil
 }
 
-func (pr *ProductRepository) invalidateProductCaches(productID, inventoryID uuid.UUID) {
+func (pr *ProductRepository) InvalidateProductCaches(productID, inventoryID uuid.UUID) {
 	pr.cache.Delete(productCacheKey(productID))
-	pr.cache.Delete(productListCacheKey(inventoryID))
+	pr.cache.Delete(productListVersionKey(inventoryID))
 	pr.cache.Delete(categoryListCacheKey(inventoryID))
 }
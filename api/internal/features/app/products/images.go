@@ -0,0 +1,251 @@
+package products
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+// Most images a single product may have
+const maxProductImages = 20
+
+func (pr *ProductRepository) GetProductImages(productID uuid.UUID) ([]models.Image, error) {
+	images, err := pr.getProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+	if images == nil {
+		images = []models.Image{}
+	}
+
+	return images, nil
+}
+
+// AddProductImages appends uploaded images after the existing ones. The first image of a
+// product without one becomes its primary image.
+func (pr *ProductRepository) AddProductImages(productID uuid.UUID, images []models.Image) ([]models.Image, error) {
+	product, err := pr.GetProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pr.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if err := appendImages(tx, productID, images); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(productID, product.InventoryID)
+
+	return pr.GetProductImages(productID)
+}
+
+// ReorderProductImages sets the display order. imageIDs must name every image of the product
+// exactly once.
+func (pr *ProductRepository) ReorderProductImages(productID uuid.UUID, imageIDs []uuid.UUID) ([]models.Image, error) {
+	product, err := pr.GetProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pr.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	existing, err := lockProductImages(tx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uuid.UUID]bool, len(existing))
+	for _, img := range existing {
+		remaining[img.ID] = true
+	}
+	if len(imageIDs) != len(existing) {
+		return nil, errors.ValidationError("The new order must list every image of the product")
+	}
+	for _, id := range imageIDs {
+		if !remaining[id] {
+			return nil, errors.ValidationError("The new order must list every image of the product exactly once")
+		}
+		delete(remaining, id)
+	}
+
+	now := time.Now()
+	for position, id := range imageIDs {
+		_, err := tx.Exec(`UPDATE product_images SET position = $1, updated_at = $2 WHERE id = $3`,
+			position, now, id)
+		if err != nil {
+			return nil, errors.DatabaseError(err, "Error reordering product images")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(productID, product.InventoryID)
+
+	return pr.GetProductImages(productID)
+}
+
+func (pr *ProductRepository) SetPrimaryProductImage(productID, imageID uuid.UUID) ([]models.Image, error) {
+	product, err := pr.GetProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pr.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := findImage(tx, productID, imageID); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE product_images SET is_primary = (id = $2), updated_at = $3
+		WHERE product_id = $1 AND is_primary <> (id = $2)`, productID, imageID, time.Now())
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error setting primary image")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(productID, product.InventoryID)
+
+	return pr.GetProductImages(productID)
+}
+
+// DeleteProductImage removes the image row and returns it so the caller can delete the stored
+// object. When the primary image is removed, the next image in order takes its place.
+func (pr *ProductRepository) DeleteProductImage(productID, imageID uuid.UUID) (*models.Image, error) {
+	product, err := pr.GetProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pr.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	image, err := findImage(tx, productID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM product_images WHERE id = $1`, imageID); err != nil {
+		return nil, errors.DatabaseError(err, "Error removing product image")
+	}
+
+	if image.IsPrimary {
+		_, err := tx.Exec(`
+			UPDATE product_images SET is_primary = true, updated_at = $2
+			WHERE id = (
+				SELECT id FROM product_images WHERE product_id = $1
+				ORDER BY position ASC, created_at ASC LIMIT 1
+			)`, productID, time.Now())
+		if err != nil {
+			return nil, errors.DatabaseError(err, "Error promoting primary image")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(productID, product.InventoryID)
+
+	return image, nil
+}
+
+// Helper functions
+
+// lockProductImages returns the product's images in order, holding the product row so
+// concurrent image changes are applied one after another
+func lockProductImages(tx *sqlx.Tx, productID uuid.UUID) ([]models.Image, error) {
+	if _, err := tx.Exec(`SELECT 1 FROM products WHERE id = $1 FOR UPDATE`, productID); err != nil {
+		return nil, errors.DatabaseError(err, "Error locking product")
+	}
+
+	var images []models.Image
+	err := tx.Select(&images, `
+		SELECT * FROM product_images WHERE product_id = $1
+		ORDER BY position ASC, created_at ASC`, productID)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching product images")
+	}
+
+	return images, nil
+}
+
+// appendImages inserts images the server stored after the product's existing ones, within the
+// 20 image limit. They are marked managed, the only images whose files may be deleted.
+func appendImages(tx *sqlx.Tx, productID uuid.UUID, images []models.Image) error {
+	existing, err := lockProductImages(tx, productID)
+	if err != nil {
+		return err
+	}
+	if len(existing)+len(images) > maxProductImages {
+		return errors.ValidationError("A product can have at most 20 images")
+	}
+
+	hasPrimary := false
+	for _, img := range existing {
+		hasPrimary = hasPrimary || img.IsPrimary
+	}
+
+	now := time.Now()
+	for i := range images {
+		img := &images[i]
+		img.ID = uuid.New()
+		img.ProductId = productID
+		img.IsPrimary = !hasPrimary && i == 0
+		img.Position = len(existing) + i
+		img.Managed = true
+		img.CreatedAt = now
+		img.UpdatedAt = now
+
+		_, err := tx.NamedExec(`
+			INSERT INTO product_images (id, url, file_key, product_id, is_primary, position, size_bytes, managed, created_at, updated_at)
+			VALUES (:id, :url, :file_key, :product_id, :is_primary, :position, :size_bytes, :managed, :created_at, :updated_at)`, img)
+		if err != nil {
+			return errors.DatabaseError(err, "Error creating product image")
+		}
+	}
+
+	return nil
+}
+
+func findImage(tx *sqlx.Tx, productID, imageID uuid.UUID) (*models.Image, error) {
+	var image models.Image
+	err := tx.Get(&image, `SELECT * FROM product_images WHERE id = $1 AND product_id = $2 FOR UPDATE`,
+		imageID, productID)
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundError("Image not found")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching product image")
+	}
+
+	return &image, nil
+}
@@ -0,0 +1,286 @@
+package products
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	maxImportFileSize = 10 << 20
+	maxImportRows     = 5000
+
+	// Separator for multiple categories or warehouses within one cell
+	importListSeparator = ";"
+)
+
+// importFields are the product fields a spreadsheet column can be mapped to
+var importFields = []string{
+	"name", "sku", "code", "brand", "model", "description", "quantity",
+	"restockLevel", "optimalLevel", "cost", "price", "categories", "warehouses",
+}
+
+// importSheet is a parsed spreadsheet with the column index of every mapped field
+type importSheet struct {
+	columns map[string]int
+	records [][]string
+}
+
+// readImportSheet parses an uploaded CSV or XLSX file. The first row holds the column headers.
+func readImportSheet(file *multipart.FileHeader, rawMapping string) (*importSheet, error) {
+	if file.Size > maxImportFileSize {
+		return nil, errors.ValidationError("Import file exceeds the 10MB limit")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.BadRequest, "Failed to open import file", 400)
+	}
+	defer src.Close()
+
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		rows, err = readCSV(src)
+	case ".xlsx":
+		rows, err = readXLSX(src)
+	default:
+		return nil, errors.ValidationError("Import file must be a .csv or .xlsx file")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.BadRequest, "Failed to parse import file", 400)
+	}
+
+	if len(rows) < 2 {
+		return nil, errors.ValidationError("Import file has no product rows")
+	}
+	if len(rows)-1 > maxImportRows {
+		return nil, errors.ValidationError(fmt.Sprintf("Import file exceeds the %d row limit", maxImportRows))
+	}
+
+	columns, err := mapImportColumns(rows[0], rawMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return &importSheet{columns: columns, records: rows[1:]}, nil
+}
+
+func readCSV(src io.Reader) ([][]string, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader.ReadAll()
+}
+
+func readXLSX(src io.Reader) ([][]string, error) {
+	workbook, err := excelize.OpenReader(src)
+	if err != nil {
+		return nil, err
+	}
+	defer workbook.Close()
+
+	sheets := workbook.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheets")
+	}
+	return workbook.GetRows(sheets[0])
+}
+
+// mapImportColumns resolves each product field to a column index. Without an explicit
+// mapping (field -> header), headers are matched to field names ignoring case, spaces,
+// dashes and underscores.
+func mapImportColumns(headers []string, rawMapping string) (map[string]int, error) {
+	headerIndex := make(map[string]int, len(headers))
+	for i, header := range headers {
+		headerIndex[normalizeHeader(header)] = i
+	}
+
+	mapping := make(map[string]string)
+	if strings.TrimSpace(rawMapping) != "" {
+		if err := json.Unmarshal([]byte(rawMapping), &mapping); err != nil {
+			return nil, errors.ValidationError("Column mapping must be a JSON object of field to column header")
+		}
+	}
+
+	columns := make(map[string]int)
+	for field, header := range mapping {
+		if !isImportField(field) {
+			return nil, errors.ValidationError(fmt.Sprintf("Unknown import field %q", field))
+		}
+		index, ok := headerIndex[normalizeHeader(header)]
+		if !ok {
+			return nil, errors.ValidationError(fmt.Sprintf("Column %q not found in import file", header))
+		}
+		columns[field] = index
+	}
+
+	for _, field := range importFields {
+		if _, mapped := columns[field]; mapped {
+			continue
+		}
+		if index, ok := headerIndex[normalizeHeader(field)]; ok {
+			columns[field] = index
+		}
+	}
+
+	if _, ok := columns["sku"]; !ok {
+		return nil, errors.ValidationError("Import file must have a SKU column")
+	}
+
+	return columns, nil
+}
+
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(header)))
+}
+
+func isImportField(field string) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// cell returns the trimmed value of a mapped field and whether the column is mapped at all
+func (s *importSheet) cell(record []string, field string) (string, bool) {
+	index, ok := s.columns[field]
+	if !ok {
+		return "", false
+	}
+	if index >= len(record) {
+		return "", true
+	}
+	return strings.TrimSpace(record[index]), true
+}
+
+// isBlank reports whether a record has no values, such as trailing spreadsheet rows
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// applyImportRecord overlays the mapped cells of a record onto req and returns the warehouse
+// references (names or IDs) of the row. Unmapped columns keep the values req already holds,
+// so updates only touch what the file provides.
+func (s *importSheet) applyImportRecord(record []string, req *models.ProductRequest) (warehouses []string, problems []string) {
+
+	text := func(field string, dest *string) {
+		if value, ok := s.cell(record, field); ok {
+			*dest = value
+		}
+	}
+	optional := func(field string, dest **string) {
+		if value, ok := s.cell(record, field); ok {
+			*dest = &value
+		}
+	}
+	integer := func(field string, dest *int) {
+		if value, ok := s.cell(record, field); ok && value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s must be a whole number", field))
+				return
+			}
+			*dest = n
+		}
+	}
+	amount := func(field string, dest *float64) {
+		if value, ok := s.cell(record, field); ok && value != "" {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s must be a number", field))
+				return
+			}
+			*dest = n
+		}
+	}
+
+	text("name", &req.Name)
+	text("sku", &req.SKU)
+	optional("code", &req.Code)
+	optional("brand", &req.Brand)
+	optional("model", &req.Model)
+	optional("description", &req.Description)
+	integer("quantity", &req.Quantity)
+	integer("restockLevel", &req.RestockLevel)
+	integer("optimalLevel", &req.OptimalLevel)
+	amount("cost", &req.Cost)
+	amount("price", &req.Price)
+
+	if value, ok := s.cell(record, "categories"); ok {
+		req.CategoryNames = splitImportList(value)
+	}
+	if value, ok := s.cell(record, "warehouses"); ok {
+		warehouses = splitImportList(value)
+	}
+
+	return warehouses, problems
+}
+
+func splitImportList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, importListSeparator) {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// resolveImportWarehouses maps warehouse names or IDs to the inventory's warehouse IDs
+func resolveImportWarehouses(refs []string, warehouses []models.Warehouse) ([]uuid.UUID, []string) {
+	var ids []uuid.UUID
+	var problems []string
+
+	for _, ref := range refs {
+		found := false
+		for _, warehouse := range warehouses {
+			if warehouse.ID.String() == strings.ToLower(ref) || strings.EqualFold(warehouse.Name, ref) {
+				ids = append(ids, warehouse.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("Warehouse %q not found in this inventory", ref))
+		}
+	}
+
+	return ids, problems
+}
+
+// describeValidation flattens struct validation failures into readable row errors
+func describeValidation(err error) []string {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	problems := make([]string, len(validationErrors))
+	for i, fe := range validationErrors {
+		if fe.Param() != "" {
+			problems[i] = fmt.Sprintf("%s failed on %s=%s", fe.Field(), fe.Tag(), fe.Param())
+		} else {
+			problems[i] = fmt.Sprintf("%s failed on %s", fe.Field(), fe.Tag())
+		}
+	}
+	return problems
+}
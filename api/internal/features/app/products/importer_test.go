@@ -0,0 +1,122 @@
+package products
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+func TestMapImportColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		mapping string
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:    "headers match field names loosely",
+			headers: []string{"Name", " SKU ", "restock_level", "Optimal-Level", "Unrelated"},
+			want:    map[string]int{"name": 0, "sku": 1, "restockLevel": 2, "optimalLevel": 3},
+		},
+		{
+			name:    "explicit mapping",
+			headers: []string{"Article", "Product Title", "Stock"},
+			mapping: `{"sku": "article", "name": "Product Title", "quantity": "STOCK"}`,
+			want:    map[string]int{"sku": 0, "name": 1, "quantity": 2},
+		},
+		{
+			name:    "explicit mapping wins over a matching header",
+			headers: []string{"SKU", "Name", "Label"},
+			mapping: `{"name": "Label"}`,
+			want:    map[string]int{"sku": 0, "name": 2},
+		},
+		{
+			name:    "blank mapping falls back to headers",
+			headers: []string{"sku"},
+			mapping: "  ",
+			want:    map[string]int{"sku": 0},
+		},
+		{
+			name:    "missing SKU column",
+			headers: []string{"Name", "Price"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			headers: []string{"SKU"},
+			mapping: `{"colour": "SKU"}`,
+			wantErr: true,
+		},
+		{
+			name:    "mapped header not in file",
+			headers: []string{"SKU"},
+			mapping: `{"name": "Title"}`,
+			wantErr: true,
+		},
+		{
+			name:    "mapping is not an object",
+			headers: []string{"SKU"},
+			mapping: `["sku"]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mapImportColumns(tc.headers, tc.mapping)
+			if tc.wantErr {
+				if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != http.StatusBadRequest {
+					t.Fatalf("expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("mapImportColumns = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyImportRecord(t *testing.T) {
+	sheet := &importSheet{columns: map[string]int{
+		"sku": 0, "name": 1, "quantity": 2, "price": 3, "brand": 4, "categories": 5, "warehouses": 6,
+	}}
+
+	brand := "Old brand"
+	req := &models.ProductRequest{Name: "Kept", Cost: 4.5, Brand: &brand, Quantity: 7}
+	warehouses, problems := sheet.applyImportRecord(
+		[]string{" SKU-1 ", "Widget", "", "12.50", "", "Tools; ;Garden", "Main;Backup"}, req)
+
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if req.SKU != "SKU-1" || req.Name != "Widget" || req.Price != 12.5 {
+		t.Errorf("mapped cells not applied: %+v", req)
+	}
+	if req.Quantity != 7 || req.Cost != 4.5 {
+		t.Errorf("blank and unmapped numbers must keep their values: %+v", req)
+	}
+	if req.Brand == nil || *req.Brand != "" {
+		t.Errorf("a blank mapped text cell clears the field, got %v", req.Brand)
+	}
+	if !reflect.DeepEqual(req.CategoryNames, []string{"Tools", "Garden"}) {
+		t.Errorf("categories = %v", req.CategoryNames)
+	}
+	if !reflect.DeepEqual(warehouses, []string{"Main", "Backup"}) {
+		t.Errorf("warehouses = %v", warehouses)
+	}
+
+	// Short records leave the missing mapped cells blank
+	_, problems = sheet.applyImportRecord([]string{"SKU-2", "Gadget", "many", "free"}, &models.ProductRequest{})
+	want := []string{"quantity must be a whole number", "price must be a number"}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v, want %v", problems, want)
+	}
+}
@@ -7,20 +7,62 @@ import (
 )
 
 type IProductRepository interface {
-	ListProducts(inventoryId uuid.UUID) ([]models.Product, error)
+	ListProducts(filter *models.ProductFilter) (*models.ProductList, error)
+	SearchProducts(inventoryId uuid.UUID, term string, limit int) ([]models.ProductSearchHit, error)
 	ListProductCategories(inventoryId uuid.UUID) ([]models.Category, error)
 	GetProduct(productId uuid.UUID) (models.Product, error)
 	GetProductWithRelations(productId uuid.UUID) (models.Product, error)
-	CreateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIds []uuid.UUID) error
-	UpdateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIds []uuid.UUID) error
+	CreateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIds []uuid.UUID, userId uuid.UUID) error
+	UpdateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIds []uuid.UUID, userId uuid.UUID) error
 	DeleteProduct(productId uuid.UUID) error
+	ImportProducts(rows []models.ProductImportRow, userId uuid.UUID) error
+	GetProductsBySKU(inventoryId uuid.UUID, skus []string) ([]models.Product, error)
+	GetInventoryWarehouses(inventoryId uuid.UUID) ([]models.Warehouse, error)
+	InvalidateProductCaches(productId, inventoryId uuid.UUID)
+
+	GetProductStock(productId uuid.UUID) ([]models.WarehouseStock, error)
+	UpdateProductWarehouseQuantity(change *models.StockChange, quantity int) (*models.StockMovement, error)
+	AdjustProductWarehouseQuantity(change *models.StockChange) (*models.StockMovement, error)
+
+	GetProductImages(productId uuid.UUID) ([]models.Image, error)
+	AddProductImages(productId uuid.UUID, images []models.Image) ([]models.Image, error)
+	ReorderProductImages(productId uuid.UUID, imageIds []uuid.UUID) ([]models.Image, error)
+	SetPrimaryProductImage(productId, imageId uuid.UUID) ([]models.Image, error)
+	DeleteProductImage(productId, imageId uuid.UUID) (*models.Image, error)
+
+	CreateImageUpload(upload *models.ImageUpload) error
+	GetImageUpload(productId, uploadId uuid.UUID) (*models.ImageUpload, error)
+	ConfirmImageUpload(productId, uploadId uuid.UUID, image models.Image) ([]models.Image, error)
+	GetExpiredImageUploads(limit int) ([]models.ImageUpload, error)
+	DeleteImageUpload(uploadId uuid.UUID) error
+
+	GetPendingImageIDs(limit int) ([]uuid.UUID, error)
+	RequeueInterruptedImages() error
+	ClaimImageProcessing(imageId uuid.UUID) (*models.Image, error)
+	CompleteImageProcessing(imageId uuid.UUID, sizeBytes int64, variants models.ImageVariants) (bool, error)
+	FailImageProcessing(imageId uuid.UUID) error
 }
 
 type IProductsController interface {
 	ListProducts(ctx echo.Context) error
+	SearchProducts(ctx echo.Context) error
 	ListProductCategories(ctx echo.Context) error
 	GetProductByID(ctx echo.Context) error
 	CreateProduct(ctx echo.Context) error
 	UpdateProduct(ctx echo.Context) error
+	PatchProduct(ctx echo.Context) error
 	DeleteProduct(ctx echo.Context) error
+	ImportProducts(ctx echo.Context) error
+
+	GetProductStock(ctx echo.Context) error
+	SetWarehouseStock(ctx echo.Context) error
+	IncrementWarehouseStock(ctx echo.Context) error
+	DecrementWarehouseStock(ctx echo.Context) error
+
+	UploadProductImages(ctx echo.Context) error
+	ReorderProductImages(ctx echo.Context) error
+	SetPrimaryProductImage(ctx echo.Context) error
+	DeleteProductImage(ctx echo.Context) error
+	CreateImageUpload(ctx echo.Context) error
+	ConfirmImageUpload(ctx echo.Context) error
 }
@@ -0,0 +1,273 @@
+package products
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/imageproc"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/storage"
+)
+
+const (
+	imageWorkers       = 2
+	imageQueueSize     = 100
+	imageSweepInterval = time.Minute
+	imageRunTimeout    = 2 * time.Minute
+
+	// Sources beyond this are not processed; uploads through the API are far smaller
+	maxSourceImageSize = 25 << 20
+)
+
+func (pr *ProductRepository) GetPendingImageIDs(limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := pr.db.Select(&ids, `
+		SELECT id FROM product_images WHERE processing_status = $1 AND managed
+		ORDER BY created_at ASC LIMIT $2`, models.ImagePending, limit)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching pending images")
+	}
+
+	return ids, nil
+}
+
+// RequeueInterruptedImages puts images a previous process was working on back in line
+func (pr *ProductRepository) RequeueInterruptedImages() error {
+	_, err := pr.db.Exec(`UPDATE product_images SET processing_status = $1 WHERE processing_status = $2`,
+		models.ImagePending, models.ImageProcessing)
+	if err != nil {
+		return errors.DatabaseError(err, "Error requeuing image processing")
+	}
+	return nil
+}
+
+// ClaimImageProcessing marks a pending image as being processed. It returns nil when another
+// worker claimed it first, the image is gone or its file was not stored by the server.
+func (pr *ProductRepository) ClaimImageProcessing(imageID uuid.UUID) (*models.Image, error) {
+	var image models.Image
+	err := pr.db.Get(&image, `
+		UPDATE product_images SET processing_status = $1
+		WHERE id = $2 AND processing_status = $3 AND managed
+		RETURNING *`, models.ImageProcessing, imageID, models.ImagePending)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error claiming image")
+	}
+
+	return &image, nil
+}
+
+// CompleteImageProcessing stores the variants of a processed image. It reports false when the
+// image was deleted while it was being processed.
+func (pr *ProductRepository) CompleteImageProcessing(imageID uuid.UUID, sizeBytes int64, variants models.ImageVariants) (bool, error) {
+	var product struct {
+		ID          uuid.UUID `db:"product_id"`
+		InventoryID uuid.UUID `db:"inventory_id"`
+	}
+	err := pr.db.Get(&product, `
+		UPDATE product_images pi
+		SET processing_status = $1, variants = $2, size_bytes = $3, updated_at = $4
+		FROM products p
+		WHERE pi.id = $5 AND p.id = pi.product_id
+		RETURNING pi.product_id, p.inventory_id`,
+		models.ImageReady, variants, sizeBytes, time.Now(), imageID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, errors.DatabaseError(err, "Error saving image variants")
+	}
+
+	pr.InvalidateProductCaches(product.ID, product.InventoryID)
+
+	return true, nil
+}
+
+func (pr *ProductRepository) FailImageProcessing(imageID uuid.UUID) error {
+	_, err := pr.db.Exec(`UPDATE product_images SET processing_status = $1 WHERE id = $2`,
+		models.ImageFailed, imageID)
+	if err != nil {
+		return errors.DatabaseError(err, "Error marking image processing as failed")
+	}
+	return nil
+}
+
+// ImageProcessor strips metadata from product images and renders their variants in the
+// background. Images are picked up when enqueued and by a periodic sweep. Only files the server
+// stored under the image prefix are processed, since processing rewrites the original.
+type ImageProcessor struct {
+	repo    IProductRepository
+	storage storage.IStorage
+	queue   chan uuid.UUID
+}
+
+func NewImageProcessor(repo IProductRepository, storage storage.IStorage) *ImageProcessor {
+	return &ImageProcessor{
+		repo:    repo,
+		storage: storage,
+		queue:   make(chan uuid.UUID, imageQueueSize),
+	}
+}
+
+// Start launches the workers and the sweep loop
+func (ip *ImageProcessor) Start(ctx context.Context) {
+	for i := 0; i < imageWorkers; i++ {
+		go ip.work(ctx)
+	}
+
+	go func() {
+		if err := ip.repo.RequeueInterruptedImages(); err != nil {
+			logger.LogError("Failed to requeue image processing", err)
+		}
+		ip.sweep(ctx)
+	}()
+}
+
+// Enqueue hands images to the workers without blocking the caller
+func (ip *ImageProcessor) Enqueue(imageIDs ...uuid.UUID) {
+	for _, id := range imageIDs {
+		select {
+		case ip.queue <- id:
+		default:
+			// The sweep picks the image up once the queue drains
+		}
+	}
+}
+
+func (ip *ImageProcessor) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case imageID := <-ip.queue:
+			ip.run(ctx, imageID)
+		}
+	}
+}
+
+func (ip *ImageProcessor) run(ctx context.Context, imageID uuid.UUID) {
+	image, err := ip.repo.ClaimImageProcessing(imageID)
+	if err != nil {
+		logger.LogError("Failed to claim image for processing", err, logger.Field("image_id", imageID))
+		return
+	}
+	if image == nil {
+		return
+	}
+	if !image.Managed || !isImageKey(image.FileKey) {
+		ip.fail(image, "Refusing to process an image the server did not store", fmt.Errorf("unmanaged key %q", image.FileKey))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imageRunTimeout)
+	defer cancel()
+
+	sizeBytes, variants, err := ip.process(ctx, image)
+	if err != nil {
+		ip.fail(image, "Failed to process product image", err)
+		return
+	}
+
+	saved, err := ip.repo.CompleteImageProcessing(imageID, sizeBytes, variants)
+	if err != nil || !saved {
+		// Either way the variants are not referenced by any image
+		for _, variant := range variants {
+			ip.storage.Delete(ctx, variant.FileKey)
+		}
+	}
+	if err != nil {
+		ip.fail(image, "Failed to save image variants", err)
+	}
+}
+
+func (ip *ImageProcessor) fail(image *models.Image, message string, err error) {
+	logger.LogError(message, err,
+		logger.Field("image_id", image.ID),
+		logger.Field("file_key", image.FileKey),
+	)
+	if err := ip.repo.FailImageProcessing(image.ID); err != nil {
+		logger.LogError("Failed to mark image processing as failed", err, logger.Field("image_id", image.ID))
+	}
+}
+
+// process replaces the original with a metadata-free copy and stores its variants. It returns
+// the new size of the original.
+func (ip *ImageProcessor) process(ctx context.Context, image *models.Image) (int64, models.ImageVariants, error) {
+	src, err := ip.storage.Open(ctx, image.FileKey)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(src, maxSourceImageSize+1))
+	src.Close()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) > maxSourceImageSize {
+		return 0, nil, fmt.Errorf("image exceeds %d bytes", maxSourceImageSize)
+	}
+
+	result, err := imageproc.Process(data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sizeBytes := int64(len(data))
+	if result.Original != nil {
+		if err := ip.storage.Put(ctx, image.FileKey, bytes.NewReader(result.Original.Data)); err != nil {
+			return 0, nil, err
+		}
+		sizeBytes = int64(len(result.Original.Data))
+	}
+
+	variants := make(models.ImageVariants, 0, len(result.Variants))
+	for _, rendered := range result.Variants {
+		key := imageproc.VariantKey(image.FileKey, rendered.Name, rendered.Ext())
+		if err := ip.storage.Put(ctx, key, bytes.NewReader(rendered.Data)); err != nil {
+			for _, stored := range variants {
+				ip.storage.Delete(ctx, stored.FileKey)
+			}
+			return 0, nil, err
+		}
+
+		variants = append(variants, models.ImageVariant{
+			Name:      rendered.Name,
+			Format:    rendered.Format,
+			FileKey:   key,
+			URL:       ip.storage.URL(key),
+			Width:     rendered.Width,
+			Height:    rendered.Height,
+			SizeBytes: int64(len(rendered.Data)),
+		})
+	}
+
+	return sizeBytes, variants, nil
+}
+
+// sweep queues pending images whenever the workers have caught up
+func (ip *ImageProcessor) sweep(ctx context.Context) {
+	ticker := time.NewTicker(imageSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if len(ip.queue) == 0 {
+			ids, err := ip.repo.GetPendingImageIDs(imageQueueSize)
+			if err != nil {
+				logger.LogError("Failed to fetch pending images", err)
+			}
+			ip.Enqueue(ids...)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
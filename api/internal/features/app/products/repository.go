@@ -1,11 +1,17 @@
 package products
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/venntry/internal/features/app/movements"
 	"github.com/venntry/internal/models"
 	"github.com/venntry/pkg/cache"
 	"github.com/venntry/pkg/errors"
@@ -13,50 +19,104 @@ import (
 
 const (
 	TTL = 7 * 24 * time.Hour
+
+	// Filtered list pages are keyed per query shape, so they expire sooner
+	listTTL = 15 * time.Minute
 )
 
 type ProductRepository struct {
-	db    *sqlx.DB
-	cache cache.IRedisService
+	db     *sqlx.DB
+	cache  cache.IRedisService
+	ledger movements.IStockLedger
 }
 
-func NewProductRepository(db *sqlx.DB, cache cache.IRedisService) IProductRepository {
-	return &ProductRepository{db: db, cache: cache}
+func NewProductRepository(db *sqlx.DB, cache cache.IRedisService, ledger movements.IStockLedger) IProductRepository {
+	return &ProductRepository{db: db, cache: cache, ledger: ledger}
 }
 
 func productCacheKey(id uuid.UUID) string {
 	return "product:" + id.String()
 }
 
-func productListCacheKey(inventoryID uuid.UUID) string {
-	return "products:" + inventoryID.String()
+func productListVersionKey(inventoryID uuid.UUID) string {
+	return "products:" + inventoryID.String() + ":version"
+}
+
+// productListCacheKey scopes a list page to the inventory's current list version, so a
+// single version bump invalidates every cached query shape at once
+func (pr *ProductRepository) productListCacheKey(filter *models.ProductFilter) string {
+	versionKey := productListVersionKey(filter.InventoryID)
+
+	var version string
+	if err := pr.cache.Get(versionKey, &version); err != nil {
+		version = uuid.NewString()
+		pr.cache.Set(versionKey, version, TTL)
+	}
+
+	shape, _ := json.Marshal(filter)
+	hash := sha256.Sum256(shape)
+
+	return fmt.Sprintf("products:%s:%s:%s", filter.InventoryID, version, hex.EncodeToString(hash[:]))
 }
 
 func categoryListCacheKey(inventoryID uuid.UUID) string {
 	return "categories:" + inventoryID.String()
 }
 
-func (pr *ProductRepository) ListProducts(inventoryID uuid.UUID) ([]models.Product, error) {
-	key := productListCacheKey(inventoryID)
+func (pr *ProductRepository) ListProducts(filter *models.ProductFilter) (*models.ProductList, error) {
+	key := pr.productListCacheKey(filter)
 
-	var cachedProducts []models.Product
-	if err := pr.cache.Get(key, &cachedProducts); err == nil {
-		return cachedProducts, nil
+	var cachedList models.ProductList
+	if err := pr.cache.Get(key, &cachedList); err == nil {
+		return &cachedList, nil
 	}
 
-	query := `SELECT * FROM products WHERE inventory_id = $1 ORDER BY created_at DESC`
-	products := []models.Product{}
+	column, ok := productSortColumns[filter.Sort]
+	if !ok {
+		return nil, errors.ValidationError("Invalid sort column")
+	}
 
-	err := pr.db.Select(&products, query, inventoryID)
-	if err != nil {
+	q := buildProductFilter(filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM products p WHERE ` + q.clause()
+	if err := pr.db.Get(&total, countQuery, q.args...); err != nil {
+		return nil, errors.DatabaseError(err, "Error counting products")
+	}
+
+	if err := q.seekAfter(filter, column); err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	query := fmt.Sprintf(`
+		SELECT p.* FROM products p
+		WHERE %s
+		ORDER BY %s %s, p.id %s
+		LIMIT %d`,
+		q.clause(), column.expr, filter.Order, filter.Order, filter.Limit+1,
+	)
+
+	products := []models.Product{}
+	if err := pr.db.Select(&products, query, q.args...); err != nil {
 		return nil, errors.DatabaseError(err, "Error fetching products")
 	}
 
-	if err := pr.cache.Set(key, products, TTL); err != nil {
-		return products, errors.CacheError(err, "Error caching products")
+	list := &models.ProductList{Products: products, Total: total}
+	if len(products) > filter.Limit {
+		list.Products = products[:filter.Limit]
+		cursor, err := nextProductCursor(filter, column, &list.Products[filter.Limit-1])
+		if err != nil {
+			return nil, err
+		}
+		list.NextCursor = cursor
 	}
 
-	return products, nil
+	if err := pr.cache.Set(key, list, listTTL); err != nil {
+		return list, errors.CacheError(err, "Error caching products")
+	}
+
+	return list, nil
 }
 
 func (pr *ProductRepository) ListProductCategories(inventoryID uuid.UUID) ([]models.Category, error) {
@@ -138,41 +198,47 @@ func (pr *ProductRepository) GetProductWithRelations(productID uuid.UUID) (model
 	return product, nil
 }
 
-func (pr *ProductRepository) CreateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIDs []uuid.UUID) error {
+func (pr *ProductRepository) CreateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIDs []uuid.UUID, userID uuid.UUID) error {
 	tx, err := pr.db.Beginx()
 	if err != nil {
 		return errors.DatabaseError(err, "Error starting transaction")
 	}
 	defer tx.Rollback()
 
-	query := `
-		INSERT INTO products (
-			id, name, sku, code, brand, model, description, quantity,
-			restock_level, optimal_level, cost, price, inventory_id,
-			created_at, updated_at
-		) VALUES (
-			:id, :name, :sku, :code, :brand, :model, :description, :quantity,
-			:restock_level, :optimal_level, :cost, :price, :inventory_id,
-			:created_at, :updated_at
-		)
-	`
-	_, err = tx.NamedExec(query, product)
+	if err := pr.insertProduct(tx, product, categoryNames, images, warehouseIDs, userID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(product.ID, product.InventoryID)
+
+	return nil
+}
+
+func (pr *ProductRepository) UpdateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIDs []uuid.UUID, userID uuid.UUID) error {
+	tx, err := pr.db.Beginx()
 	if err != nil {
-		return errors.DatabaseError(err, "Error creating product")
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if err := pr.updateProductRow(tx, product, userID); err != nil {
+		return err
 	}
 
-	// Handle product images
-	if err := pr.createProductImages(tx, product.ID, images); err != nil {
+	// Reconcile relations against the request, keeping unchanged links intact
+	if err := pr.reconcileProductImages(tx, product.ID, images); err != nil {
 		return err
 	}
 
-	// Handle product categories
-	if err := pr.linkProductCategories(tx, product.ID, product.InventoryID, categoryNames); err != nil {
+	if err := pr.reconcileProductCategories(tx, product.ID, product.InventoryID, categoryNames); err != nil {
 		return err
 	}
 
-	// Handle product warehouses
-	if err := pr.linkProductWarehouses(tx, product.ID, warehouseIDs); err != nil {
+	if err := pr.reconcileProductWarehouses(tx, product.ID, warehouseIDs, userID); err != nil {
 		return err
 	}
 
@@ -180,58 +246,104 @@ func (pr *ProductRepository) CreateProduct(product *models.Product, categoryName
 		return errors.DatabaseError(err, "Error committing transaction")
 	}
 
-	pr.invalidateProductCaches(product.ID, product.InventoryID)
+	pr.InvalidateProductCaches(product.ID, product.InventoryID)
 
 	return nil
 }
 
-func (pr *ProductRepository) UpdateProduct(product *models.Product, categoryNames []string, images []models.ImageRequest, warehouseIDs []uuid.UUID) error {
+func (pr *ProductRepository) GetProductStock(productID uuid.UUID) ([]models.WarehouseStock, error) {
+	stock := []models.WarehouseStock{}
+	query := `
+		SELECT w.id AS warehouse_id, w.name, w.capacity, wp.quantity
+		FROM warehouse_product_map wp
+		INNER JOIN warehouses w ON w.id = wp.warehouse_id
+		WHERE wp.product_id = $1
+		ORDER BY w.name ASC
+	`
+	if err := pr.db.Select(&stock, query, productID); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching product stock")
+	}
+
+	return stock, nil
+}
+
+// UpdateProductWarehouseQuantity sets the product's quantity in one warehouse
+func (pr *ProductRepository) UpdateProductWarehouseQuantity(change *models.StockChange, quantity int) (*models.StockMovement, error) {
+	return pr.changeWarehouseStock(change, func(tx *sqlx.Tx) (*models.StockMovement, error) {
+		return pr.ledger.SetStock(tx, change, quantity)
+	})
+}
+
+// AdjustProductWarehouseQuantity increments or decrements the product's quantity in one warehouse
+func (pr *ProductRepository) AdjustProductWarehouseQuantity(change *models.StockChange) (*models.StockMovement, error) {
+	return pr.changeWarehouseStock(change, func(tx *sqlx.Tx) (*models.StockMovement, error) {
+		return pr.ledger.AdjustStock(tx, change)
+	})
+}
+
+// ImportProducts writes every imported row in a single transaction. Existing products are
+// matched by SKU; their categories and warehouses are extended, never removed.
+func (pr *ProductRepository) ImportProducts(rows []models.ProductImportRow, userID uuid.UUID) error {
 	tx, err := pr.db.Beginx()
 	if err != nil {
 		return errors.DatabaseError(err, "Error starting transaction")
 	}
 	defer tx.Rollback()
 
-	query := `
-		UPDATE products SET
-			name = :name, sku = :sku, code = :code, brand = :brand,
-			model = :model, description = :description, quantity = :quantity,
-			restock_level = :restock_level, optimal_level = :optimal_level,
-			cost = :cost, price = :price, updated_at = :updated_at
-		WHERE id = :id
-	`
-	_, err = tx.NamedExec(query, product)
-	if err != nil {
-		return errors.DatabaseError(err, "Error updating product")
-	}
-
-	// Clear existing relations
-	if err := pr.clearProductRelations(tx, product.ID); err != nil {
-		return err
-	}
+	for _, row := range rows {
+		if !row.Existing {
+			if err := pr.insertProduct(tx, row.Product, row.CategoryNames, nil, row.WarehouseIDs, userID); err != nil {
+				return err
+			}
+			continue
+		}
 
-	// Recreate relations
-	if err := pr.createProductImages(tx, product.ID, images); err != nil {
-		return err
-	}
+		if err := pr.updateProductRow(tx, row.Product, userID); err != nil {
+			return err
+		}
 
-	if err := pr.linkProductCategories(tx, product.ID, product.InventoryID, categoryNames); err != nil {
-		return err
-	}
+		if err := pr.linkProductCategories(tx, row.Product.ID, row.Product.InventoryID, row.CategoryNames); err != nil {
+			return err
+		}
 
-	if err := pr.linkProductWarehouses(tx, product.ID, warehouseIDs); err != nil {
-		return err
+		if err := pr.linkProductWarehouses(tx, row.Product.ID, row.WarehouseIDs, userID); err != nil {
+			return err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
 		return errors.DatabaseError(err, "Error committing transaction")
 	}
 
-	pr.invalidateProductCaches(product.ID, product.InventoryID)
+	for _, row := range rows {
+		pr.InvalidateProductCaches(row.Product.ID, row.Product.InventoryID)
+	}
 
 	return nil
 }
 
+func (pr *ProductRepository) GetProductsBySKU(inventoryID uuid.UUID, skus []string) ([]models.Product, error) {
+	products := []models.Product{}
+	query := `SELECT * FROM products WHERE inventory_id = $1 AND sku = ANY($2)`
+
+	if err := pr.db.Select(&products, query, inventoryID, pq.Array(skus)); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching products by SKU")
+	}
+
+	return products, nil
+}
+
+func (pr *ProductRepository) GetInventoryWarehouses(inventoryID uuid.UUID) ([]models.Warehouse, error) {
+	warehouses := []models.Warehouse{}
+	query := `SELECT * FROM warehouses WHERE inventory_id = $1 ORDER BY name ASC`
+
+	if err := pr.db.Select(&warehouses, query, inventoryID); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching warehouses")
+	}
+
+	return warehouses, nil
+}
+
 func (pr *ProductRepository) DeleteProduct(productID uuid.UUID) error {
 	product, err := pr.GetProduct(productID)
 	if err != nil {
@@ -244,7 +356,7 @@ func (pr *ProductRepository) DeleteProduct(productID uuid.UUID) error {
 		return errors.DatabaseError(err, "Error deleting product")
 	}
 
-	pr.invalidateProductCaches(productID, product.InventoryID)
+	pr.InvalidateProductCaches(productID, product.InventoryID)
 
 	return nil
 }
@@ -0,0 +1,85 @@
+package products
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+// productSearchDocument must stay in sync with idx_products_search_document
+const productSearchDocument = `to_tsvector('simple',
+	COALESCE(p.name, '') || ' ' || COALESCE(p.sku, '') || ' ' || COALESCE(p.code, '') || ' ' ||
+	COALESCE(p.brand, '') || ' ' || COALESCE(p.model, '') || ' ' || COALESCE(p.description, ''))`
+
+// Exact SKU/code hits sort first, then full-text rank plus the best trigram similarity.
+// Highlights are HTML: the source text is escaped before ts_headline wraps matches in <mark>.
+const productSearchQuery = `
+	SELECT p.*,
+		(LOWER(COALESCE(p.sku, '')) = LOWER($2::text) OR LOWER(COALESCE(p.code, '')) = LOWER($2::text)) AS exact_match,
+		ts_rank({document}, to_tsquery('simple', $3)) + GREATEST(
+			similarity(p.name, $2::text),
+			word_similarity($2::text, p.name),
+			similarity(COALESCE(p.sku, ''), $2::text),
+			similarity(COALESCE(p.code, ''), $2::text),
+			similarity(COALESCE(p.brand, ''), $2::text),
+			similarity(COALESCE(p.model, ''), $2::text),
+			COALESCE(cat.similarity, 0)
+		) AS score,
+		ts_headline('simple', html_escape(p.name), to_tsquery('simple', $3), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+		ts_headline('simple', html_escape(p.brand), to_tsquery('simple', $3), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS brand_highlight,
+		ts_headline('simple', html_escape(p.model), to_tsquery('simple', $3), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS model_highlight,
+		ts_headline('simple', html_escape(p.description), to_tsquery('simple', $3), 'StartSel=<mark>, StopSel=</mark>, MaxWords=25, MinWords=10') AS description_highlight,
+		cat.names AS category_names,
+		ts_headline('simple', html_escape(cat.names), to_tsquery('simple', $3), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS category_highlight
+	FROM products p
+	LEFT JOIN LATERAL (
+		SELECT string_agg(c.name, ', ' ORDER BY c.name) AS names, MAX(similarity(c.name, $2::text)) AS similarity
+		FROM product_category_map m
+		INNER JOIN product_categories c ON c.id = m.category_id
+		WHERE m.product_id = p.id
+	) cat ON TRUE
+	WHERE p.inventory_id = $1 AND (
+		{document} @@ to_tsquery('simple', $3)
+		OR to_tsvector('simple', COALESCE(cat.names, '')) @@ to_tsquery('simple', $3)
+		OR LOWER(COALESCE(p.sku, '')) = LOWER($2::text)
+		OR LOWER(COALESCE(p.code, '')) = LOWER($2::text)
+		OR p.name % $2::text
+		OR $2::text <% p.name
+		OR p.sku % $2::text
+		OR p.code % $2::text
+		OR p.brand % $2::text
+		OR p.model % $2::text
+		OR cat.similarity >= 0.3
+	)
+	ORDER BY exact_match DESC, score DESC, p.name ASC
+	LIMIT $4`
+
+func (pr *ProductRepository) SearchProducts(inventoryID uuid.UUID, term string, limit int) ([]models.ProductSearchHit, error) {
+	query := strings.ReplaceAll(productSearchQuery, "{document}", productSearchDocument)
+
+	hits := []models.ProductSearchHit{}
+	err := pr.db.Select(&hits, query, inventoryID, term, prefixTSQuery(term), limit)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error searching products")
+	}
+
+	return hits, nil
+}
+
+// prefixTSQuery turns free text into a tsquery matching every word as a prefix, so
+// partial names like "gal sam" still find "Samsung Galaxy". Anything other than letters
+// and digits is treated as a separator, which keeps user input from breaking the syntax.
+func prefixTSQuery(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
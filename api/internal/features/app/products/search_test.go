@@ -0,0 +1,25 @@
+package products
+
+import "testing"
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{"gal sam", "gal:* & sam:*"},
+		{"Samsung", "samsung:*"},
+		{"  usb-c   cable ", "usb:* & c:* & cable:*"},
+		{"café 4k", "café:* & 4k:*"},
+		{"a & b | !c", "a:* & b:* & c:*"},
+		{"it's (foo):*", "it:* & s:* & foo:*"},
+		{"<>&|!():*'", ""},
+		{"", ""},
+	}
+
+	for _, tc := range tests {
+		if got := prefixTSQuery(tc.term); got != tc.want {
+			t.Errorf("prefixTSQuery(%q) = %q, want %q", tc.term, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package products
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/storage"
+)
+
+const (
+	// How long a presigned upload URL accepts the file
+	uploadURLTTL = 15 * time.Minute
+	// How long the client has to confirm an upload before it is collected
+	uploadConfirmTTL = time.Hour
+
+	uploadCleanupInterval = 15 * time.Minute
+	uploadCleanupBatch    = 100
+)
+
+// CreateImageUpload records a pending direct upload. Pending uploads count towards the
+// product's image limit so a client cannot sign more uploads than it could ever confirm.
+func (pr *ProductRepository) CreateImageUpload(upload *models.ImageUpload) error {
+	tx, err := pr.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	existing, err := lockProductImages(tx, upload.ProductId)
+	if err != nil {
+		return err
+	}
+
+	var pending int
+	err = tx.Get(&pending, `SELECT COUNT(*) FROM image_uploads WHERE product_id = $1 AND expires_at > $2`,
+		upload.ProductId, time.Now())
+	if err != nil {
+		return errors.DatabaseError(err, "Error counting pending uploads")
+	}
+	if len(existing)+pending >= maxProductImages {
+		return errors.ValidationError("A product can have at most 20 images")
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO image_uploads (id, product_id, user_id, file_key, content_type, size_bytes, created_at, expires_at)
+		VALUES (:id, :product_id, :user_id, :file_key, :content_type, :size_bytes, :created_at, :expires_at)`, upload)
+	if err != nil {
+		return errors.DatabaseError(err, "Error creating image upload")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+// GetImageUpload returns an unconfirmed upload that has not expired yet
+func (pr *ProductRepository) GetImageUpload(productID, uploadID uuid.UUID) (*models.ImageUpload, error) {
+	var upload models.ImageUpload
+	err := pr.db.Get(&upload, `
+		SELECT * FROM image_uploads WHERE id = $1 AND product_id = $2 AND expires_at > $3`,
+		uploadID, productID, time.Now())
+	if err == sql.ErrNoRows {
+		return nil, errors.NotFoundError("Upload not found or expired")
+	} else if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching image upload")
+	}
+
+	return &upload, nil
+}
+
+// ConfirmImageUpload turns a pending upload into a product image. Removing the pending row in
+// the same transaction makes confirming a single-use operation.
+func (pr *ProductRepository) ConfirmImageUpload(productID, uploadID uuid.UUID, image models.Image) ([]models.Image, error) {
+	product, err := pr.GetProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pr.db.Beginx()
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM image_uploads WHERE id = $1 AND product_id = $2 AND expires_at > $3`,
+		uploadID, productID, time.Now())
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error confirming image upload")
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, errors.NotFoundError("Upload not found or expired")
+	}
+
+	if err := appendImages(tx, productID, []models.Image{image}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	pr.InvalidateProductCaches(productID, product.InventoryID)
+
+	return pr.GetProductImages(productID)
+}
+
+func (pr *ProductRepository) GetExpiredImageUploads(limit int) ([]models.ImageUpload, error) {
+	var uploads []models.ImageUpload
+	err := pr.db.Select(&uploads, `
+		SELECT * FROM image_uploads WHERE expires_at <= $1
+		ORDER BY expires_at ASC LIMIT $2`, time.Now(), limit)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching expired image uploads")
+	}
+
+	return uploads, nil
+}
+
+func (pr *ProductRepository) DeleteImageUpload(uploadID uuid.UUID) error {
+	if _, err := pr.db.Exec(`DELETE FROM image_uploads WHERE id = $1`, uploadID); err != nil {
+		return errors.DatabaseError(err, "Error deleting image upload")
+	}
+	return nil
+}
+
+// UploadCollector removes direct uploads that were never confirmed, along with any object
+// the client managed to put in the bucket
+type UploadCollector struct {
+	repo    IProductRepository
+	storage storage.IStorage
+}
+
+func NewUploadCollector(repo IProductRepository, storage storage.IStorage) *UploadCollector {
+	return &UploadCollector{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+// Start launches the cleanup loop
+func (uc *UploadCollector) Start(ctx context.Context) {
+	go uc.cleanup(ctx)
+}
+
+func (uc *UploadCollector) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(uploadCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		uc.collect(ctx)
+	}
+}
+
+// collect deletes the objects before their rows, so a failed deletion is retried on the
+// next run instead of leaving an untracked object behind
+func (uc *UploadCollector) collect(ctx context.Context) {
+	for {
+		uploads, err := uc.repo.GetExpiredImageUploads(uploadCleanupBatch)
+		if err != nil {
+			logger.LogError("Failed to fetch expired image uploads", err)
+			return
+		}
+
+		removed := 0
+		for _, upload := range uploads {
+			if err := uc.storage.Delete(ctx, upload.FileKey); err != nil {
+				logger.LogError("Failed to delete unconfirmed upload from storage", err,
+					logger.Field("file_key", upload.FileKey))
+				continue
+			}
+			if err := uc.repo.DeleteImageUpload(upload.ID); err != nil {
+				logger.LogError("Failed to delete expired image upload", err, logger.Field("upload_id", upload.ID))
+				continue
+			}
+			removed++
+		}
+
+		// Stop when the backlog is cleared or nothing could be removed, and retry next tick
+		if len(uploads) < uploadCleanupBatch || removed == 0 {
+			return
+		}
+	}
+}
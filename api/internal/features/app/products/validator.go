@@ -21,10 +21,25 @@ func NewProductValidator(db *sqlx.DB) *ProductValidator {
 }
 
 func (pv *ProductValidator) ValidateProduct(product *models.Product) error {
+	return pv.validate(product, func(string) bool { return true })
+}
+
+// ValidateProductFields validates a partially updated product, checking only the given JSON fields
+func (pv *ProductValidator) ValidateProductFields(product *models.Product, fields []string) error {
+	touched := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		touched[field] = true
+	}
+
+	return pv.validate(product, func(field string) bool { return touched[field] })
+}
+
+// Helper methods
+func (pv *ProductValidator) validate(product *models.Product, touched func(field string) bool) error {
 	var errorMessages []string
 
 	//  Default product code generation
-	if product.Code == nil || *product.Code == "" {
+	if touched("code") && (product.Code == nil || *product.Code == "") {
 		generatedCode, err := pv.generateProductCode(product.Name)
 		if err != nil {
 			return errors.DatabaseError(err, "Error generating product code")
@@ -33,23 +48,25 @@ func (pv *ProductValidator) ValidateProduct(product *models.Product) error {
 	}
 
 	// Default restock and optimal levels
-	if product.RestockLevel == 0 {
+	if touched("restockLevel") && product.RestockLevel == 0 {
 		product.RestockLevel = 10
 	}
-	if product.OptimalLevel == 0 {
+	if touched("optimalLevel") && product.OptimalLevel == 0 {
 		product.OptimalLevel = 100
 	}
 
 	// Validate unique fields
-	nameExists, err := pv.fieldExists("name", product.Name, product.InventoryID, product.ID)
-	if err != nil {
-		return errors.DatabaseError(err, "Error validating product name")
-	}
-	if nameExists {
-		errorMessages = append(errorMessages, "Product name already exists")
+	if touched("name") {
+		nameExists, err := pv.fieldExists("name", product.Name, product.InventoryID, product.ID)
+		if err != nil {
+			return errors.DatabaseError(err, "Error validating product name")
+		}
+		if nameExists {
+			errorMessages = append(errorMessages, "Product name already exists")
+		}
 	}
 
-	if product.SKU != "" {
+	if touched("sku") && product.SKU != "" {
 		skuExists, err := pv.fieldExists("sku", product.SKU, product.InventoryID, product.ID)
 		if err != nil {
 			return errors.DatabaseError(err, "Error validating product SKU")
@@ -59,7 +76,7 @@ func (pv *ProductValidator) ValidateProduct(product *models.Product) error {
 		}
 	}
 
-	if product.Code != nil && *product.Code != "" {
+	if touched("code") && product.Code != nil && *product.Code != "" {
 		codeExists, err := pv.fieldExists("code", *product.Code, product.InventoryID, product.ID)
 		if err != nil {
 			return errors.DatabaseError(err, "Error validating product code")
@@ -76,7 +93,6 @@ func (pv *ProductValidator) ValidateProduct(product *models.Product) error {
 	return nil
 }
 
-// Helper methods
 func (pv *ProductValidator) fieldExists(fieldName, fieldValue string, inventoryID, productID uuid.UUID) (bool, error) {
 	query := `
 		SELECT EXISTS(
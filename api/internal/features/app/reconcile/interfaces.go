@@ -0,0 +1,8 @@
+package reconcile
+
+import "github.com/venntry/internal/models"
+
+type IReconcileRepository interface {
+	GetImageObjects() ([]models.StoredImageObject, error)
+	GetPendingUploadKeys() ([]string, error)
+}
@@ -0,0 +1,142 @@
+package reconcile
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/logger"
+	"github.com/venntry/pkg/storage"
+)
+
+// Bucket prefix holding product images and their variants
+const imagePrefix = "products/"
+
+// Reconciler compares the product images in the bucket with product_images. Objects no row
+// refers to are deleted once they outlive the grace period, which covers uploads and variants
+// written just before their row. Rows whose object is gone are reported.
+type Reconciler struct {
+	repo    IReconcileRepository
+	storage storage.IStorage
+	grace   time.Duration
+}
+
+func NewReconciler(repo IReconcileRepository, storage storage.IStorage, grace time.Duration) *Reconciler {
+	return &Reconciler{
+		repo:    repo,
+		storage: storage,
+		grace:   grace,
+	}
+}
+
+// Start runs the reconciliation every interval
+func (r *Reconciler) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if _, err := r.Run(ctx, false); err != nil {
+				logger.LogError("Storage reconciliation failed", err)
+			}
+		}
+	}()
+}
+
+// Run reconciles once. With dryRun, orphans are reported but kept.
+func (r *Reconciler) Run(ctx context.Context, dryRun bool) (*models.ReconcileReport, error) {
+	report := &models.ReconcileReport{
+		StartedAt: time.Now(),
+		DryRun:    dryRun,
+		Orphans:   []models.OrphanedObject{},
+		Missing:   []models.StoredImageObject{},
+	}
+
+	// List the bucket before reading the database, so an object uploaded in between is never
+	// seen without its row
+	objects, err := r.storage.List(ctx, imagePrefix)
+	if err != nil {
+		return nil, err
+	}
+	report.ObjectsScanned = len(objects)
+
+	images, err := r.repo.GetImageObjects()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := r.repo.GetPendingUploadKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool, len(images)+len(pending))
+	for _, image := range images {
+		referenced[image.FileKey] = true
+	}
+	for _, key := range pending {
+		referenced[key] = true
+	}
+	report.ReferencedKeys = len(referenced)
+
+	stored := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		stored[object.Key] = true
+		if referenced[object.Key] {
+			continue
+		}
+		if report.StartedAt.Sub(object.LastModified) < r.grace {
+			report.OrphansInGrace++
+			continue
+		}
+
+		orphan := models.OrphanedObject{
+			Key:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		}
+		if !dryRun {
+			if err := r.storage.Delete(ctx, object.Key); err != nil {
+				logger.LogError("Failed to delete orphaned object", err, logger.Field("file_key", object.Key))
+			} else {
+				orphan.Deleted = true
+				report.OrphansDeleted++
+			}
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+
+	// Rows changed since the listing started may point at objects it could not see
+	for _, image := range images {
+		if !strings.HasPrefix(image.FileKey, imagePrefix) || stored[image.FileKey] || !image.UpdatedAt.Before(report.StartedAt) {
+			continue
+		}
+		report.Missing = append(report.Missing, image)
+	}
+
+	report.FinishedAt = time.Now()
+
+	logger.Info("Storage reconciliation finished",
+		logger.Field("dry_run", dryRun),
+		logger.Field("objects_scanned", report.ObjectsScanned),
+		logger.Field("orphans", len(report.Orphans)),
+		logger.Field("orphans_deleted", report.OrphansDeleted),
+		logger.Field("orphans_in_grace", report.OrphansInGrace),
+		logger.Field("missing", len(report.Missing)),
+	)
+	for _, missing := range report.Missing {
+		logger.Info("Product image object is missing from storage",
+			logger.Field("image_id", missing.ImageID),
+			logger.Field("product_id", missing.ProductID),
+			logger.Field("file_key", missing.FileKey),
+		)
+	}
+
+	return report, nil
+}
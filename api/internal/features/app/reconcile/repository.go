@@ -0,0 +1,42 @@
+package reconcile
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type ReconcileRepository struct {
+	db *sqlx.DB
+}
+
+func NewReconcileRepository(db *sqlx.DB) IReconcileRepository {
+	return &ReconcileRepository{db: db}
+}
+
+// GetImageObjects lists every key product images refer to, variants included
+func (rr *ReconcileRepository) GetImageObjects() ([]models.StoredImageObject, error) {
+	var objects []models.StoredImageObject
+	err := rr.db.Select(&objects, `
+		SELECT pi.id AS image_id, pi.product_id, pi.file_key, pi.updated_at
+		FROM product_images pi
+		UNION ALL
+		SELECT pi.id, pi.product_id, v->>'fileKey', pi.updated_at
+		FROM product_images pi
+		CROSS JOIN LATERAL jsonb_array_elements(pi.variants) v`)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching product image keys")
+	}
+
+	return objects, nil
+}
+
+// GetPendingUploadKeys lists the keys reserved by direct uploads awaiting confirmation
+func (rr *ReconcileRepository) GetPendingUploadKeys() ([]string, error) {
+	var keys []string
+	if err := rr.db.Select(&keys, `SELECT file_key FROM image_uploads`); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching pending upload keys")
+	}
+
+	return keys, nil
+}
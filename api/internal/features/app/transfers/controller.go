@@ -0,0 +1,177 @@
+package transfers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/features/app/products"
+	"github.com/venntry/internal/features/app/warehouses"
+	"github.com/venntry/internal/mappers"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+type TransferController struct {
+	repo          ITransferRepository
+	warehouseRepo warehouses.IWarehouseRepository
+	productRepo   products.IProductRepository
+}
+
+func NewTransferController(repo ITransferRepository, warehouseRepo warehouses.IWarehouseRepository, productRepo products.IProductRepository) ITransferController {
+	return &TransferController{
+		repo:          repo,
+		warehouseRepo: warehouseRepo,
+		productRepo:   productRepo,
+	}
+}
+
+func (tc *TransferController) ListTransfers(ctx echo.Context) error {
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	status := ctx.QueryParam("status")
+	switch status {
+	case "", models.TransferInTransit, models.TransferCompleted, models.TransferCancelled:
+	default:
+		return errors.ValidationError("Invalid transfer status")
+	}
+
+	transfers, err := tc.repo.ListTransfers(inventoryID, status)
+	if err != nil {
+		return logger.Error(ctx, "Failed to fetch transfers", err,
+			logger.Field("inventory_id", inventoryID),
+		)
+	}
+
+	response := make([]models.TransferResponse, len(transfers))
+	for i, transfer := range transfers {
+		response[i] = *mappers.ToTransferResponse(&transfer)
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (tc *TransferController) GetTransfer(ctx echo.Context) error {
+	transfer, err := tc.findTransfer(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, mappers.ToTransferResponse(transfer))
+}
+
+func (tc *TransferController) CreateTransfer(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return errors.ValidationError("Invalid inventory ID")
+	}
+
+	var req models.TransferRequest
+	if err := utils.BindAndValidateRequest(ctx, &req); err != nil {
+		return err
+	}
+
+	mappers.SanitizeTransferRequest(&req)
+	transfer := mappers.ToCreateTransfer(&req, inventoryID, user.Id)
+
+	if transfer.SourceWarehouseID == transfer.DestinationWarehouseID {
+		return errors.ValidationError("Source and destination warehouses must differ")
+	}
+
+	seen := make(map[uuid.UUID]bool, len(transfer.Items))
+	for _, item := range transfer.Items {
+		if seen[item.ProductID] {
+			return errors.ValidationError("Each product may only appear once per transfer")
+		}
+		seen[item.ProductID] = true
+	}
+
+	if err := tc.repo.CreateTransfer(transfer); err != nil {
+		return logger.Error(ctx, "Failed to create transfer", err,
+			logger.Field("inventory_id", inventoryID),
+			logger.Field("source_warehouse_id", transfer.SourceWarehouseID),
+			logger.Field("destination_warehouse_id", transfer.DestinationWarehouseID),
+		)
+	}
+
+	tc.invalidateCaches(transfer)
+
+	response := mappers.ToTransferResponse(transfer)
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (tc *TransferController) ReceiveTransfer(ctx echo.Context) error {
+	return tc.closeTransfer(ctx, tc.repo.ReceiveTransfer, "Failed to receive transfer")
+}
+
+func (tc *TransferController) CancelTransfer(ctx echo.Context) error {
+	return tc.closeTransfer(ctx, tc.repo.CancelTransfer, "Failed to cancel transfer")
+}
+
+// Helper methods
+func (tc *TransferController) closeTransfer(ctx echo.Context, close func(*models.Transfer, uuid.UUID) error, message string) error {
+	user, ok := ctx.Get("user").(*models.User)
+	if !ok {
+		return errors.New(errors.Unauthorized, "User not authenticated", http.StatusUnauthorized)
+	}
+
+	transfer, err := tc.findTransfer(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := close(transfer, user.Id); err != nil {
+		return logger.Error(ctx, message, err,
+			logger.Field("transfer_id", transfer.ID),
+		)
+	}
+
+	tc.invalidateCaches(transfer)
+
+	return ctx.JSON(http.StatusOK, mappers.ToTransferResponse(transfer))
+}
+
+func (tc *TransferController) findTransfer(ctx echo.Context) (*models.Transfer, error) {
+	inventoryID, err := uuid.Parse(ctx.Param("inventoryId"))
+	if err != nil {
+		return nil, errors.ValidationError("Invalid inventory ID")
+	}
+
+	transferID, err := uuid.Parse(ctx.Param("transferId"))
+	if err != nil {
+		return nil, errors.ValidationError("Invalid transfer ID")
+	}
+
+	transfer, err := tc.repo.GetTransfer(transferID)
+	if err != nil {
+		return nil, logger.Error(ctx, "Failed to retrieve transfer", err,
+			logger.Field("transfer_id", transferID),
+		)
+	}
+
+	if transfer.InventoryID != inventoryID {
+		return nil, errors.NotFoundError("Transfer not found")
+	}
+
+	return &transfer, nil
+}
+
+// invalidateCaches drops both warehouses and every moved product, whose per-warehouse stock
+// and derived quantity changed with the transfer
+func (tc *TransferController) invalidateCaches(transfer *models.Transfer) {
+	tc.warehouseRepo.InvalidateWarehouseCaches(transfer.SourceWarehouseID, transfer.InventoryID)
+	tc.warehouseRepo.InvalidateWarehouseCaches(transfer.DestinationWarehouseID, transfer.InventoryID)
+	for _, item := range transfer.Items {
+		tc.productRepo.InvalidateProductCaches(item.ProductID, transfer.InventoryID)
+	}
+}
@@ -0,0 +1,23 @@
+package transfers
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/models"
+)
+
+type ITransferRepository interface {
+	ListTransfers(inventoryID uuid.UUID, status string) ([]models.Transfer, error)
+	GetTransfer(transferID uuid.UUID) (models.Transfer, error)
+	CreateTransfer(transfer *models.Transfer) error
+	ReceiveTransfer(transfer *models.Transfer, userID uuid.UUID) error
+	CancelTransfer(transfer *models.Transfer, userID uuid.UUID) error
+}
+
+type ITransferController interface {
+	ListTransfers(ctx echo.Context) error
+	GetTransfer(ctx echo.Context) error
+	CreateTransfer(ctx echo.Context) error
+	ReceiveTransfer(ctx echo.Context) error
+	CancelTransfer(ctx echo.Context) error
+}
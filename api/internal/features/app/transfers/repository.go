@@ -0,0 +1,267 @@
+package transfers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/venntry/internal/features/app/movements"
+	"github.com/venntry/internal/models"
+	"github.com/venntry/pkg/errors"
+)
+
+type TransferRepository struct {
+	db     *sqlx.DB
+	ledger movements.IStockLedger
+}
+
+func NewTransferRepository(db *sqlx.DB, ledger movements.IStockLedger) ITransferRepository {
+	return &TransferRepository{db: db, ledger: ledger}
+}
+
+func (tr *TransferRepository) ListTransfers(inventoryID uuid.UUID, status string) ([]models.Transfer, error) {
+	transfers := []models.Transfer{}
+	query := `
+		SELECT * FROM stock_transfers
+		WHERE inventory_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`
+	if err := tr.db.Select(&transfers, query, inventoryID, status); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching transfers")
+	}
+
+	for i := range transfers {
+		items, err := tr.getTransferItems(transfers[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		transfers[i].Items = items
+	}
+
+	return transfers, nil
+}
+
+func (tr *TransferRepository) GetTransfer(transferID uuid.UUID) (models.Transfer, error) {
+	var transfer models.Transfer
+	query := `SELECT * FROM stock_transfers WHERE id = $1`
+
+	err := tr.db.Get(&transfer, query, transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return transfer, errors.NotFoundError("Transfer not found")
+		}
+		return transfer, errors.DatabaseError(err, "Error getting transfer")
+	}
+
+	items, err := tr.getTransferItems(transferID)
+	if err != nil {
+		return transfer, err
+	}
+	transfer.Items = items
+
+	return transfer, nil
+}
+
+// CreateTransfer ships every item out of the source warehouse. Transfers that are not
+// left in transit are received at the destination within the same transaction.
+func (tr *TransferRepository) CreateTransfer(transfer *models.Transfer) error {
+	tx, err := tr.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if err := tr.lockWarehouses(tx, transfer); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO stock_transfers (
+			id, inventory_id, source_warehouse_id, destination_warehouse_id, status,
+			reference, created_by, shipped_at, created_at, updated_at
+		) VALUES (
+			:id, :inventory_id, :source_warehouse_id, :destination_warehouse_id, :status,
+			:reference, :created_by, :shipped_at, :created_at, :updated_at
+		)
+	`
+	if _, err := tx.NamedExec(query, transfer); err != nil {
+		return errors.DatabaseError(err, "Error creating transfer")
+	}
+
+	for _, item := range transfer.Items {
+		if err := tr.checkProduct(tx, item.ProductID, transfer.InventoryID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO stock_transfer_items (transfer_id, product_id, quantity)
+			VALUES ($1, $2, $3)
+		`, transfer.ID, item.ProductID, item.Quantity)
+		if err != nil {
+			return errors.DatabaseError(err, "Error creating transfer item")
+		}
+
+		if err := tr.moveStock(tx, transfer, transfer.SourceWarehouseID, item, -item.Quantity, transfer.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	if transfer.Status == models.TransferCompleted {
+		if err := tr.receiveItems(tx, transfer, transfer.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	return nil
+}
+
+func (tr *TransferRepository) ReceiveTransfer(transfer *models.Transfer, userID uuid.UUID) error {
+	return tr.closeTransfer(transfer, userID, models.TransferCompleted)
+}
+
+// CancelTransfer returns in-transit stock to the source warehouse.
+func (tr *TransferRepository) CancelTransfer(transfer *models.Transfer, userID uuid.UUID) error {
+	return tr.closeTransfer(transfer, userID, models.TransferCancelled)
+}
+
+// Helper methods
+func (tr *TransferRepository) closeTransfer(transfer *models.Transfer, userID uuid.UUID, status string) error {
+	tx, err := tr.db.Beginx()
+	if err != nil {
+		return errors.DatabaseError(err, "Error starting transaction")
+	}
+	defer tx.Rollback()
+
+	if err := tr.lockWarehouses(tx, transfer); err != nil {
+		return err
+	}
+
+	// Re-read the status under lock so a transfer cannot be closed twice
+	var current string
+	err = tx.Get(&current, `SELECT status FROM stock_transfers WHERE id = $1 FOR UPDATE`, transfer.ID)
+	if err != nil {
+		return errors.DatabaseError(err, "Error locking transfer")
+	}
+	if current != models.TransferInTransit {
+		return errors.ConflictError("Transfer is no longer in transit")
+	}
+
+	if status == models.TransferCompleted {
+		if err := tr.receiveItems(tx, transfer, &userID); err != nil {
+			return err
+		}
+	} else {
+		for _, item := range transfer.Items {
+			// The product may have been unlinked from the source while the stock was on its way
+			if err := tr.ledger.LinkWarehouse(tx, item.ProductID, transfer.SourceWarehouseID, &userID); err != nil {
+				return err
+			}
+
+			if err := tr.moveStock(tx, transfer, transfer.SourceWarehouseID, item, item.Quantity, &userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+		UPDATE stock_transfers
+		SET status = $1, received_by = $2, received_at = $3, updated_at = $3
+		WHERE id = $4
+	`, status, userID, now, transfer.ID)
+	if err != nil {
+		return errors.DatabaseError(err, "Error updating transfer")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	transfer.Status = status
+	transfer.ReceivedBy = &userID
+	transfer.ReceivedAt = &now
+	transfer.UpdatedAt = now
+
+	return nil
+}
+
+// lockWarehouses locks both warehouse rows in a stable order so concurrent transfers
+// between the same pair cannot deadlock, and verifies they belong to the inventory.
+func (tr *TransferRepository) lockWarehouses(tx *sqlx.Tx, transfer *models.Transfer) error {
+	var warehouses []models.Warehouse
+	err := tx.Select(&warehouses, `
+		SELECT * FROM warehouses
+		WHERE id IN ($1, $2) AND inventory_id = $3
+		ORDER BY id
+		FOR UPDATE
+	`, transfer.SourceWarehouseID, transfer.DestinationWarehouseID, transfer.InventoryID)
+	if err != nil {
+		return errors.DatabaseError(err, "Error locking warehouses")
+	}
+	if len(warehouses) != 2 {
+		return errors.NotFoundError("Source or destination warehouse not found")
+	}
+
+	return nil
+}
+
+func (tr *TransferRepository) checkProduct(tx *sqlx.Tx, productID, inventoryID uuid.UUID) error {
+	var exists bool
+	err := tx.Get(&exists, `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND inventory_id = $2)`,
+		productID, inventoryID)
+	if err != nil {
+		return errors.DatabaseError(err, "Error validating transfer product")
+	}
+	if !exists {
+		return errors.NotFoundError("Product not found: " + productID.String())
+	}
+
+	return nil
+}
+
+// receiveItems books every item into the destination; the ledger enforces its capacity.
+func (tr *TransferRepository) receiveItems(tx *sqlx.Tx, transfer *models.Transfer, userID *uuid.UUID) error {
+	for _, item := range transfer.Items {
+		// Products not yet stocked at the destination get a warehouse link first
+		if err := tr.ledger.LinkWarehouse(tx, item.ProductID, transfer.DestinationWarehouseID, userID); err != nil {
+			return err
+		}
+
+		if err := tr.moveStock(tx, transfer, transfer.DestinationWarehouseID, item, item.Quantity, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tr *TransferRepository) moveStock(tx *sqlx.Tx, transfer *models.Transfer, warehouseID uuid.UUID, item models.TransferItem, delta int, userID *uuid.UUID) error {
+	reference := "transfer:" + transfer.ID.String()
+	change := &models.StockChange{
+		ProductID:   item.ProductID,
+		WarehouseID: &warehouseID,
+		Delta:       delta,
+		Reason:      models.ReasonTransfer,
+		Reference:   &reference,
+		UserID:      userID,
+	}
+
+	_, err := tr.ledger.AdjustStock(tx, change)
+	return err
+}
+
+func (tr *TransferRepository) getTransferItems(transferID uuid.UUID) ([]models.TransferItem, error) {
+	items := []models.TransferItem{}
+	query := `SELECT * FROM stock_transfer_items WHERE transfer_id = $1`
+
+	if err := tr.db.Select(&items, query, transferID); err != nil {
+		return nil, errors.DatabaseError(err, "Error fetching transfer items")
+	}
+
+	return items, nil
+}
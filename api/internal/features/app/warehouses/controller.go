@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/venntry/internal/features/app/products"
 	"github.com/venntry/internal/mappers"
 	"github.com/venntry/internal/models"
 	"github.com/venntry/internal/shared/utils"
@@ -13,14 +14,16 @@ import (
 )
 
 type WarehouseController struct {
-	repo      IWarehouseRepository
-	validator *WarehouseValidator
+	repo        IWarehouseRepository
+	validator   *WarehouseValidator
+	productRepo products.IProductRepository
 }
 
-func NewWarehouseController(repo IWarehouseRepository, validator *WarehouseValidator) IWarehouseController {
+func NewWarehouseController(repo IWarehouseRepository, validator *WarehouseValidator, productRepo products.IProductRepository) IWarehouseController {
 	return &WarehouseController{
-		repo:      repo,
-		validator: validator,
+		repo:        repo,
+		validator:   validator,
+		productRepo: productRepo,
 	}
 }
 
@@ -125,17 +128,68 @@ func (wc *WarehouseController) UpdateWarehouse(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// PatchWarehouse applies a JSON merge patch to a warehouse
+func (wc *WarehouseController) PatchWarehouse(ctx echo.Context) error {
+	warehouseID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return errors.ValidationError("Invalid warehouse ID")
+	}
+
+	existingWarehouse, err := wc.repo.GetWarehouse(warehouseID)
+	if err != nil {
+		return logger.Error(ctx, "Warehouse not found", err,
+			logger.Field("warehouse_id", warehouseID),
+		)
+	}
+
+	req := mappers.ToWarehouseRequest(&existingWarehouse)
+	fields, err := utils.BindMergePatch(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	mappers.SanitizeWarehouseRequest(req)
+
+	updatedWarehouse := mappers.ToUpdateWarehouse(req, &existingWarehouse)
+	if err := wc.validator.ValidateWarehouseFields(updatedWarehouse, fields); err != nil {
+		return err
+	}
+
+	if err := wc.repo.UpdateWarehouse(updatedWarehouse); err != nil {
+		return logger.Error(ctx, "Failed to patch warehouse", err,
+			logger.Field("warehouse_id", warehouseID),
+			logger.Field("fields", fields),
+		)
+	}
+
+	response := mappers.ToWarehouseResponse(updatedWarehouse)
+	return ctx.JSON(http.StatusOK, response)
+}
+
 func (wc *WarehouseController) DeleteWarehouse(ctx echo.Context) error {
 	warehouseID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
 		return errors.ValidationError("Invalid warehouse ID")
 	}
 
-	if err := wc.repo.DeleteWarehouse(warehouseID); err != nil {
+	warehouse, err := wc.repo.GetWarehouse(warehouseID)
+	if err != nil {
+		return logger.Error(ctx, "Warehouse not found", err,
+			logger.Field("warehouse_id", warehouseID),
+		)
+	}
+
+	productIDs, err := wc.repo.DeleteWarehouse(warehouseID)
+	if err != nil {
 		return logger.Error(ctx, "Failed to delete warehouse", err,
 			logger.Field("warehouse_id", warehouseID),
 		)
 	}
 
+	// The products no longer list the warehouse among their stock locations
+	for _, productID := range productIDs {
+		wc.productRepo.InvalidateProductCaches(productID, warehouse.InventoryID)
+	}
+
 	return ctx.NoContent(http.StatusNoContent)
 }
@@ -12,7 +12,8 @@ type IWarehouseRepository interface {
 	GetWarehouseWithProducts(warehouseID uuid.UUID) (models.Warehouse, error)
 	CreateWarehouse(warehouse *models.Warehouse) error
 	UpdateWarehouse(warehouse *models.Warehouse) error
-	DeleteWarehouse(warehouseID uuid.UUID) error
+	DeleteWarehouse(warehouseID uuid.UUID) ([]uuid.UUID, error)
+	InvalidateWarehouseCaches(warehouseID, inventoryID uuid.UUID)
 }
 
 type IWarehouseController interface {
@@ -20,5 +21,6 @@ type IWarehouseController interface {
 	GetWarehouse(ctx echo.Context) error
 	CreateWarehouse(ctx echo.Context) error
 	UpdateWarehouse(ctx echo.Context) error
+	PatchWarehouse(ctx echo.Context) error
 	DeleteWarehouse(ctx echo.Context) error
 }
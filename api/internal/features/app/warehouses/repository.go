@@ -116,7 +116,7 @@ func (wr *WarehouseRepository) CreateWarehouse(warehouse *models.Warehouse) erro
 		return errors.DatabaseError(err, "Error creating warehouse")
 	}
 
-	wr.invalidateWarehouseCaches(warehouse.ID, warehouse.InventoryID)
+	wr.InvalidateWarehouseCaches(warehouse.ID, warehouse.InventoryID)
 
 	return nil
 }
@@ -135,29 +135,80 @@ func (wr *WarehouseRepository) UpdateWarehouse(warehouse *models.Warehouse) erro
 		return errors.DatabaseError(err, "Error updating warehouse")
 	}
 
-	wr.invalidateWarehouseCaches(warehouse.ID, warehouse.InventoryID)
+	wr.InvalidateWarehouseCaches(warehouse.ID, warehouse.InventoryID)
 
 	return nil
 }
 
-func (wr *WarehouseRepository) DeleteWarehouse(warehouseID uuid.UUID) error {
+// DeleteWarehouse refuses to delete a warehouse that still holds stock or has transfers in
+// transit, whose stock would otherwise vanish without a ledger entry. The warehouse row is
+// locked like stock changes and transfers lock it, so neither can start while the checks run.
+// It returns the products whose (empty) link to the warehouse was removed.
+func (wr *WarehouseRepository) DeleteWarehouse(warehouseID uuid.UUID) ([]uuid.UUID, error) {
 	warehouse, err := wr.GetWarehouse(warehouseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	query := `DELETE FROM warehouses WHERE id = $1`
-	_, err = wr.db.Exec(query, warehouseID)
+	tx, err := wr.db.Beginx()
 	if err != nil {
-		return errors.DatabaseError(err, "Error deleting warehouse")
+		return nil, errors.DatabaseError(err, "Error starting transaction")
 	}
+	defer tx.Rollback()
 
-	wr.invalidateWarehouseCaches(warehouseID, warehouse.InventoryID)
+	var locked uuid.UUID
+	err = tx.Get(&locked, `SELECT id FROM warehouses WHERE id = $1 FOR UPDATE`, warehouseID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFoundError("Warehouse not found")
+		}
+		return nil, errors.DatabaseError(err, "Error locking warehouse")
+	}
 
-	return nil
+	var inTransit bool
+	err = tx.Get(&inTransit, `
+		SELECT EXISTS(
+			SELECT 1 FROM stock_transfers
+			WHERE (source_warehouse_id = $1 OR destination_warehouse_id = $1) AND status = $2
+		)`, warehouseID, models.TransferInTransit)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error checking warehouse transfers")
+	}
+	if inTransit {
+		return nil, errors.ConflictError("Warehouse has transfers in transit; receive or cancel them first")
+	}
+
+	var stocked bool
+	err = tx.Get(&stocked, `SELECT EXISTS(SELECT 1 FROM warehouse_product_map WHERE warehouse_id = $1 AND quantity > 0)`,
+		warehouseID)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error checking warehouse stock")
+	}
+	if stocked {
+		return nil, errors.ConflictError("Warehouse still holds stock; transfer or adjust it out first")
+	}
+
+	// Only empty links remain, so no product quantity changes with them
+	productIDs := []uuid.UUID{}
+	err = tx.Select(&productIDs, `DELETE FROM warehouse_product_map WHERE warehouse_id = $1 RETURNING product_id`, warehouseID)
+	if err != nil {
+		return nil, errors.DatabaseError(err, "Error unlinking warehouse products")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM warehouses WHERE id = $1`, warehouseID); err != nil {
+		return nil, errors.DatabaseError(err, "Error deleting warehouse")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.DatabaseError(err, "Error committing transaction")
+	}
+
+	wr.InvalidateWarehouseCaches(warehouseID, warehouse.InventoryID)
+
+	return productIDs, nil
 }
 
-func (wr *WarehouseRepository) invalidateWarehouseCaches(warehouseID, inventoryID uuid.UUID) {
+func (wr *WarehouseRepository) InvalidateWarehouseCaches(warehouseID, inventoryID uuid.UUID) {
 	wr.cache.Delete(warehouseCacheKey(warehouseID))
 	wr.cache.Delete(warehouseListCacheKey(inventoryID))
 }
@@ -2,6 +2,8 @@ package warehouses
 
 import (
 	"database/sql"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -19,16 +21,35 @@ func NewWarehouseValidator(db *sqlx.DB) *WarehouseValidator {
 }
 
 func (wv *WarehouseValidator) ValidateWarehouse(warehouse *models.Warehouse) error {
+	return wv.ValidateWarehouseFields(warehouse, []string{"name", "capacity"})
+}
+
+// ValidateWarehouseFields validates a partially updated warehouse, checking only the given JSON fields
+func (wv *WarehouseValidator) ValidateWarehouseFields(warehouse *models.Warehouse, fields []string) error {
 	var errorMessages []string
 
 	// Name uniqueness check
-	exists, err := wv.warehouseExists(warehouse.Name, warehouse.InventoryID, warehouse.ID)
-	if err != nil {
-		return errors.DatabaseError(err, "Error validating warehouse")
+	if slices.Contains(fields, "name") {
+		exists, err := wv.warehouseExists(warehouse.Name, warehouse.InventoryID, warehouse.ID)
+		if err != nil {
+			return errors.DatabaseError(err, "Error validating warehouse")
+		}
+
+		if exists {
+			errorMessages = append(errorMessages, "Warehouse name already exists")
+		}
 	}
 
-	if exists {
-		errorMessages = append(errorMessages, "Warehouse name already exists")
+	// A capacity cannot be lowered below the stock already stored
+	if slices.Contains(fields, "capacity") && warehouse.Capacity != nil {
+		stored, err := wv.storedQuantity(warehouse.ID)
+		if err != nil {
+			return errors.DatabaseError(err, "Error validating warehouse")
+		}
+
+		if *warehouse.Capacity < stored {
+			errorMessages = append(errorMessages, fmt.Sprintf("Capacity cannot be below the %d units currently stored", stored))
+		}
 	}
 
 	if len(errorMessages) > 0 {
@@ -52,3 +73,11 @@ func (wv *WarehouseValidator) warehouseExists(warehouseName string, inventoryID,
 	}
 	return exists, nil
 }
+
+func (wv *WarehouseValidator) storedQuantity(warehouseID uuid.UUID) (int, error) {
+	const query = `SELECT COALESCE(SUM(quantity), 0) FROM warehouse_product_map WHERE warehouse_id = $1`
+
+	var stored int
+	err := wv.db.Get(&stored, query, warehouseID)
+	return stored, err
+}
@@ -0,0 +1,98 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+)
+
+func ToExportQuery(req *models.ExportRequest, inventoryID uuid.UUID, exportType string) *models.ExportQuery {
+	query := &models.ExportQuery{
+		InventoryID: inventoryID,
+		Type:        exportType,
+		Format:      req.Format,
+		Reason:      req.Reason,
+	}
+
+	if query.Format == "" {
+		query.Format = models.ExportFormatCSV
+	}
+
+	if id, err := uuid.Parse(req.Warehouse); err == nil {
+		query.WarehouseID = &id
+	}
+	if id, err := uuid.Parse(req.Product); err == nil {
+		query.ProductID = &id
+	}
+
+	// Dates are whole days; the upper bound includes the whole "to" day
+	if from, err := time.Parse(time.DateOnly, req.From); err == nil {
+		query.From = &from
+	}
+	if to, err := time.Parse(time.DateOnly, req.To); err == nil {
+		to = to.AddDate(0, 0, 1)
+		query.To = &to
+	}
+
+	if exportType == models.ExportProducts {
+		query.Products = ToProductFilter(&models.ProductListRequest{
+			Category:  req.Category,
+			Brand:     req.Brand,
+			Warehouse: req.Warehouse,
+			MinPrice:  req.MinPrice,
+			MaxPrice:  req.MaxPrice,
+			Stock:     req.Stock,
+		}, inventoryID)
+	}
+
+	return query
+}
+
+func ToExportJob(query *models.ExportQuery, userID uuid.UUID, ttl time.Duration) (*models.ExportJob, error) {
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &models.ExportJob{
+		ID:          uuid.New(),
+		InventoryID: query.InventoryID,
+		UserID:      userID,
+		Type:        query.Type,
+		Format:      query.Format,
+		Status:      models.ExportJobPending,
+		Query:       encoded,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}, nil
+}
+
+func ToExportJobResponse(job *models.ExportJob) *models.ExportJobResponse {
+	response := &models.ExportJobResponse{
+		ID:          job.ID,
+		Type:        job.Type,
+		Format:      job.Format,
+		Status:      job.Status,
+		RowCount:    job.RowCount,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+
+	if job.Status == models.ExportJobCompleted {
+		url := fmt.Sprintf("/api/inventories/%s/exports/jobs/%s/download", job.InventoryID, job.ID)
+		response.DownloadURL = &url
+	}
+
+	return response
+}
+
+// ExportFileName names a download after its dataset and the day it was produced
+func ExportFileName(exportType, format string, at time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", exportType, at.Format(time.DateOnly), format)
+}
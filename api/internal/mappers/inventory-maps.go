@@ -25,16 +25,30 @@ func ToEditInventory(req *models.InventoryRequest, existing *models.Inventory) *
 	return existing
 }
 
+func ToInventoryRequest(inv *models.Inventory) *models.InventoryRequest {
+	return &models.InventoryRequest{
+		Name: inv.Name,
+	}
+}
+
 func ToInventoryResponse(inv *models.Inventory) *models.InventoryResponse {
 	return &models.InventoryResponse{
-		Id:        inv.Id,
-		Name:      inv.Name,
-		UserId:    inv.UserId,
-		CreatedAt: inv.CreatedAt,
-		UpdatedAt: inv.UpdatedAt,
+		Id:               inv.Id,
+		Name:             inv.Name,
+		UserId:           inv.UserId,
+		RequireTwoFactor: inv.RequireTwoFactor,
+		CreatedAt:        inv.CreatedAt,
+		UpdatedAt:        inv.UpdatedAt,
 	}
 }
 
+func ToUserInventoryResponse(inv *models.UserInventory) *models.InventoryResponse {
+	response := ToInventoryResponse(&inv.Inventory)
+	response.Role = inv.Role
+
+	return response
+}
+
 func SanitizeInventoryRequest(req *models.InventoryRequest) {
 	req.Name = strings.TrimSpace(req.Name)
 }
@@ -0,0 +1,30 @@
+package mappers
+
+import (
+	"github.com/venntry/internal/models"
+)
+
+func ToMemberResponse(member *models.MemberDetails) *models.MemberResponse {
+	return &models.MemberResponse{
+		UserId:    member.UserId,
+		Username:  member.Username,
+		Email:     member.Email,
+		Avatar:    member.Avatar,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
+
+func ToInvitationResponse(invitation *models.Invitation) *models.InvitationResponse {
+	return &models.InvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		Status:    invitation.Status,
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+		UpdatedAt: invitation.UpdatedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"github.com/venntry/internal/models"
+)
+
+func ToStockMovementResponse(movement *models.StockMovement) *models.StockMovementResponse {
+	return &models.StockMovementResponse{
+		ID:          movement.ID,
+		ProductID:   movement.ProductID,
+		WarehouseID: movement.WarehouseID,
+		Delta:       movement.Delta,
+		Balance:     movement.Balance,
+		Reason:      movement.Reason,
+		Reference:   movement.Reference,
+		UserID:      movement.UserID,
+		CreatedAt:   movement.CreatedAt,
+	}
+}
+
+func ToStockMovementPage(movements []models.StockMovement, total, page, limit int) *models.StockMovementPage {
+	response := &models.StockMovementPage{
+		Movements: make([]models.StockMovementResponse, len(movements)),
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}
+
+	for i, movement := range movements {
+		response.Movements[i] = *ToStockMovementResponse(&movement)
+	}
+
+	return response
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/venntry/internal/models"
+	"github.com/venntry/internal/shared/utils"
 )
 
 // Convert float64 to cents (int)
@@ -14,6 +15,14 @@ func convertToCents(value float64) int {
 	return int(math.Round(value * 100))
 }
 
+func optionalCents(value *float64) *int {
+	if value == nil {
+		return nil
+	}
+	cents := convertToCents(*value)
+	return &cents
+}
+
 func ToCreateProduct(req *models.ProductRequest, inventoryID uuid.UUID) *models.Product {
 	now := time.Now()
 	return &models.Product{
@@ -58,6 +67,62 @@ func ToUpdateProduct(req *models.ProductRequest, existing *models.Product) *mode
 	}
 }
 
+// ToProductRequest mirrors a stored product as a request, the base document for merge patches
+func ToProductRequest(product *models.Product) *models.ProductRequest {
+	req := &models.ProductRequest{
+		Name:          product.Name,
+		SKU:           product.SKU,
+		Code:          product.Code,
+		Brand:         product.Brand,
+		Model:         product.Model,
+		Description:   product.Description,
+		Quantity:      product.Quantity,
+		RestockLevel:  product.RestockLevel,
+		OptimalLevel:  product.OptimalLevel,
+		Cost:          float64(product.Cost) / 100,
+		Price:         float64(product.Price) / 100,
+		ImageData:     make([]models.ImageRequest, len(product.Images)),
+		CategoryNames: make([]string, len(product.Categories)),
+		WarehouseIDs:  make([]string, len(product.Warehouses)),
+	}
+
+	for i, img := range product.Images {
+		req.ImageData[i] = models.ImageRequest{
+			ID:        img.ID.String(),
+			IsPrimary: img.IsPrimary,
+		}
+	}
+
+	for i, cat := range product.Categories {
+		req.CategoryNames[i] = cat.Name
+	}
+
+	for i, warehouse := range product.Warehouses {
+		req.WarehouseIDs[i] = warehouse.ID.String()
+	}
+
+	return req
+}
+
+func ToImageResponse(img *models.Image) *models.ImageResponse {
+	variants := []models.ImageVariant(img.Variants)
+	if variants == nil {
+		variants = []models.ImageVariant{}
+	}
+
+	return &models.ImageResponse{
+		ID:        img.ID,
+		URL:       img.URL,
+		FileKey:   img.FileKey,
+		IsPrimary: img.IsPrimary,
+		Position:  img.Position,
+		Status:    img.Status,
+		Variants:  variants,
+		CreatedAt: img.CreatedAt,
+		UpdatedAt: img.UpdatedAt,
+	}
+}
+
 func ToProductResponse(product *models.Product) *models.ProductResponse {
 	response := &models.ProductResponse{
 		ID:           product.ID,
@@ -80,14 +145,7 @@ func ToProductResponse(product *models.Product) *models.ProductResponse {
 	if len(product.Images) > 0 {
 		response.Images = make([]models.ImageResponse, len(product.Images))
 		for i, img := range product.Images {
-			response.Images[i] = models.ImageResponse{
-				ID:        img.ID,
-				URL:       img.URL,
-				FileKey:   img.FileKey,
-				IsPrimary: img.IsPrimary,
-				CreatedAt: img.CreatedAt,
-				UpdatedAt: img.UpdatedAt,
-			}
+			response.Images[i] = *ToImageResponse(&img)
 		}
 	}
 
@@ -122,6 +180,93 @@ func ToProductResponse(product *models.Product) *models.ProductResponse {
 	return response
 }
 
+func ToProductFilter(req *models.ProductListRequest, inventoryID uuid.UUID) *models.ProductFilter {
+	filter := &models.ProductFilter{
+		InventoryID: inventoryID,
+		Cursor:      req.Cursor,
+		Limit:       req.Limit,
+		Sort:        req.Sort,
+		Order:       req.Order,
+		Brand:       strings.TrimSpace(req.Brand),
+		MinPrice:    optionalCents(req.MinPrice),
+		MaxPrice:    optionalCents(req.MaxPrice),
+		MinCost:     optionalCents(req.MinCost),
+		MaxCost:     optionalCents(req.MaxCost),
+		Stock:       req.Stock,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = utils.DefaultPageLimit
+	}
+
+	// Newest first unless another column is requested
+	if filter.Sort == "" {
+		filter.Sort = models.ProductSortCreatedAt
+	}
+	if filter.Order == "" {
+		filter.Order = "asc"
+		if filter.Sort == models.ProductSortCreatedAt || filter.Sort == models.ProductSortUpdatedAt {
+			filter.Order = "desc"
+		}
+	}
+
+	if id, err := uuid.Parse(req.Category); err == nil {
+		filter.CategoryID = &id
+	}
+	if id, err := uuid.Parse(req.Warehouse); err == nil {
+		filter.WarehouseID = &id
+	}
+
+	return filter
+}
+
+func ToProductPage(list *models.ProductList, limit int) *models.ProductPage {
+	page := &models.ProductPage{
+		Products:   make([]models.ProductResponse, len(list.Products)),
+		Total:      list.Total,
+		Limit:      limit,
+		NextCursor: list.NextCursor,
+	}
+
+	for i, product := range list.Products {
+		page.Products[i] = *ToProductResponse(&product)
+	}
+
+	return page
+}
+
+func ToProductSearchResponse(query string, hits []models.ProductSearchHit) *models.ProductSearchResponse {
+	response := &models.ProductSearchResponse{
+		Query:   query,
+		Results: make([]models.ProductSearchResult, len(hits)),
+	}
+
+	for i, hit := range hits {
+		highlights := make(map[string]string)
+		addHighlight(highlights, "name", hit.NameHighlight)
+		addHighlight(highlights, "brand", hit.BrandHighlight)
+		addHighlight(highlights, "model", hit.ModelHighlight)
+		addHighlight(highlights, "description", hit.DescriptionHighlight)
+		addHighlight(highlights, "categories", hit.CategoryHighlight)
+
+		response.Results[i] = models.ProductSearchResult{
+			Product:    *ToProductResponse(&hit.Product),
+			Score:      hit.Score,
+			ExactMatch: hit.ExactMatch,
+			Highlights: highlights,
+		}
+	}
+
+	return response
+}
+
+// addHighlight keeps only fragments where the search actually marked a match
+func addHighlight(highlights map[string]string, field string, fragment *string) {
+	if fragment != nil && strings.Contains(*fragment, "<mark>") {
+		highlights[field] = *fragment
+	}
+}
+
 func ToCategoryResponse(category *models.Category) *models.CategoryResponse {
 	return &models.CategoryResponse{
 		Id:        category.ID,
@@ -131,6 +276,45 @@ func ToCategoryResponse(category *models.Category) *models.CategoryResponse {
 	}
 }
 
+func ToProductStockResponse(product *models.Product, stock []models.WarehouseStock) *models.ProductStockResponse {
+	response := &models.ProductStockResponse{
+		ProductID:  product.ID,
+		Quantity:   product.Quantity,
+		Warehouses: make([]models.WarehouseStockResponse, len(stock)),
+	}
+
+	for i, ws := range stock {
+		response.Warehouses[i] = models.WarehouseStockResponse{
+			WarehouseID:   ws.WarehouseID,
+			WarehouseName: ws.WarehouseName,
+			Capacity:      ws.Capacity,
+			Quantity:      ws.Quantity,
+		}
+	}
+
+	return response
+}
+
+func ToStockChange(productID, warehouseID, userID uuid.UUID, delta int, reason string, reference *string) *models.StockChange {
+	if reference != nil {
+		trimmed := strings.TrimSpace(*reference)
+		if trimmed == "" {
+			reference = nil
+		} else {
+			reference = &trimmed
+		}
+	}
+
+	return &models.StockChange{
+		ProductID:   productID,
+		WarehouseID: &warehouseID,
+		Delta:       delta,
+		Reason:      reason,
+		Reference:   reference,
+		UserID:      &userID,
+	}
+}
+
 func SanitizeProductRequest(req *models.ProductRequest) {
 	req.Name = strings.TrimSpace(req.Name)
 	req.SKU = strings.TrimSpace(req.SKU)
@@ -0,0 +1,80 @@
+package mappers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/internal/models"
+)
+
+func ToCreateTransfer(req *models.TransferRequest, inventoryID, userID uuid.UUID) *models.Transfer {
+	now := time.Now()
+	transfer := &models.Transfer{
+		ID:                     uuid.New(),
+		InventoryID:            inventoryID,
+		SourceWarehouseID:      uuid.MustParse(req.SourceWarehouseID),
+		DestinationWarehouseID: uuid.MustParse(req.DestinationWarehouseID),
+		Status:                 models.TransferCompleted,
+		Reference:              req.Reference,
+		CreatedBy:              &userID,
+		ShippedAt:              now,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+		Items:                  make([]models.TransferItem, len(req.Items)),
+	}
+
+	if req.Ship {
+		transfer.Status = models.TransferInTransit
+	} else {
+		transfer.ReceivedBy = &userID
+		transfer.ReceivedAt = &now
+	}
+
+	for i, item := range req.Items {
+		transfer.Items[i] = models.TransferItem{
+			TransferID: transfer.ID,
+			ProductID:  uuid.MustParse(item.ProductID),
+			Quantity:   item.Quantity,
+		}
+	}
+
+	return transfer
+}
+
+func ToTransferResponse(transfer *models.Transfer) *models.TransferResponse {
+	response := &models.TransferResponse{
+		ID:                     transfer.ID,
+		SourceWarehouseID:      transfer.SourceWarehouseID,
+		DestinationWarehouseID: transfer.DestinationWarehouseID,
+		Status:                 transfer.Status,
+		Reference:              transfer.Reference,
+		CreatedBy:              transfer.CreatedBy,
+		ReceivedBy:             transfer.ReceivedBy,
+		ShippedAt:              transfer.ShippedAt,
+		ReceivedAt:             transfer.ReceivedAt,
+		CreatedAt:              transfer.CreatedAt,
+		UpdatedAt:              transfer.UpdatedAt,
+		Items:                  make([]models.TransferItemResponse, len(transfer.Items)),
+	}
+
+	for i, item := range transfer.Items {
+		response.Items[i] = models.TransferItemResponse{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return response
+}
+
+func SanitizeTransferRequest(req *models.TransferRequest) {
+	if req.Reference != nil {
+		trimmed := strings.TrimSpace(*req.Reference)
+		if trimmed == "" {
+			req.Reference = nil
+		} else {
+			req.Reference = &trimmed
+		}
+	}
+}
@@ -32,6 +32,14 @@ func ToUpdateWarehouse(req *models.WarehouseRequest, existing *models.Warehouse)
 	}
 }
 
+func ToWarehouseRequest(warehouse *models.Warehouse) *models.WarehouseRequest {
+	return &models.WarehouseRequest{
+		Name:     warehouse.Name,
+		Location: warehouse.Location,
+		Capacity: warehouse.Capacity,
+	}
+}
+
 func ToWarehouseResponse(warehouse *models.Warehouse) *models.WarehouseResponse {
 	response := &models.WarehouseResponse{
 		ID:        warehouse.ID,
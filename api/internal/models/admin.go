@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Audited administrator actions
+const (
+	AuditUserSuspend         = "user.suspend"
+	AuditUserReactivate      = "user.reactivate"
+	AuditUserPasswordReset   = "user.password_reset"
+	AuditUserUnlock          = "user.unlock"
+	AuditImpersonationStart  = "impersonation.start"
+	AuditImpersonatedRequest = "impersonation.request"
+
+	AuditTargetUser = "user"
+)
+
+// AdminUser is a user as listed to system administrators
+type AdminUser struct {
+	Id              uuid.UUID  `db:"id" json:"id"`
+	Username        string     `db:"username" json:"username"`
+	Email           string     `db:"email" json:"email"`
+	Role            string     `db:"role" json:"role"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"emailVerifiedAt"`
+	SuspendedAt     *time.Time `db:"suspended_at" json:"suspendedAt"`
+	SuspendedReason *string    `db:"suspended_reason" json:"suspendedReason"`
+	InventoryCount  int        `db:"inventory_count" json:"inventoryCount"`
+	LastSeenAt      *time.Time `db:"last_seen_at" json:"lastSeenAt"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+}
+
+type AdminUserFilter struct {
+	Search string `query:"q" validate:"omitempty,max=100"`
+	Status string `query:"status" validate:"omitempty,oneof=active suspended"`
+	Role   string `query:"role" validate:"omitempty,max=20"`
+}
+
+type AdminUserPage struct {
+	Users []AdminUser `json:"users"`
+	Total int         `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+// AdminInventory is an inventory a user belongs to, with its usage
+type AdminInventory struct {
+	Id             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	OwnerId        uuid.UUID `db:"user_id" json:"ownerId"`
+	Role           string    `db:"role" json:"role"`
+	ProductCount   int       `db:"product_count" json:"productCount"`
+	WarehouseCount int       `db:"warehouse_count" json:"warehouseCount"`
+	MemberCount    int       `db:"member_count" json:"memberCount"`
+	StorageBytes   int64     `db:"storage_bytes" json:"storageBytes"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+type ImpersonationRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+// ImpersonationResponse is a short-lived access token for acting as another user. It has no
+// refresh token; the session ends when it expires or is logged out.
+type ImpersonationResponse struct {
+	SessionID uuid.UUID `json:"sessionId"`
+	UserID    uuid.UUID `json:"userId"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// AuditEntry records one administrator action
+type AuditEntry struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	AdminID    *uuid.UUID     `db:"admin_id" json:"adminId"`
+	Action     string         `db:"action" json:"action"`
+	TargetType string         `db:"target_type" json:"targetType"`
+	TargetID   *uuid.UUID     `db:"target_id" json:"targetId"`
+	Details    types.JSONText `db:"details" json:"details"`
+	IPAddress  string         `db:"ip_address" json:"ipAddress"`
+	CreatedAt  time.Time      `db:"created_at" json:"createdAt"`
+}
+
+type AuditLogFilter struct {
+	AdminID  string `query:"adminId" validate:"omitempty,uuid"`
+	TargetID string `query:"targetId" validate:"omitempty,uuid"`
+	Action   string `query:"action" validate:"omitempty,max=50"`
+}
+
+type AuditLogPage struct {
+	Entries []AuditEntry `json:"entries"`
+	Total   int          `json:"total"`
+	Page    int          `json:"page"`
+	Limit   int          `json:"limit"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix marks bearer tokens that are API keys rather than JWTs
+const APIKeyPrefix = "vnt_"
+
+// API key scopes
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// APIKey lets machine clients authenticate as a user. Keys bound to an inventory only work
+// on that inventory's routes. Only the hash of the secret is stored.
+type APIKey struct {
+	ID          uuid.UUID  `db:"id" json:"id"`
+	UserID      uuid.UUID  `db:"user_id" json:"userId"`
+	InventoryID *uuid.UUID `db:"inventory_id" json:"inventoryId"`
+	Name        string     `db:"name" json:"name"`
+	Prefix      string     `db:"prefix" json:"prefix"`
+	KeyHash     string     `db:"key_hash" json:"-"`
+	Scope       string     `db:"scope" json:"scope"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	ExpiresAt   *time.Time `db:"expires_at" json:"expiresAt"`
+	LastUsedAt  *time.Time `db:"last_used_at" json:"lastUsedAt"`
+	RevokedAt   *time.Time `db:"revoked_at" json:"revokedAt"`
+}
+
+type APIKeyRequest struct {
+	Name          string     `json:"name" validate:"required,max=100"`
+	Scope         string     `json:"scope" validate:"required,oneof=read write"`
+	InventoryID   *uuid.UUID `json:"inventoryId"`
+	ExpiresInDays *int       `json:"expiresInDays" validate:"omitempty,min=1,max=365"`
+}
+
+// APIKeyCreated is returned once, when a key is created or rotated; the secret is not
+// retrievable afterwards
+type APIKeyCreated struct {
+	APIKey
+	Secret string `json:"secret"`
+}
+
+// IsActive reports whether the key may still be used to authenticate
+func (key *APIKey) IsActive() bool {
+	return key.RevokedAt == nil && (key.ExpiresAt == nil || time.Now().Before(*key.ExpiresAt))
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Export datasets, formats and job statuses
+const (
+	ExportProducts   = "products"
+	ExportWarehouses = "warehouses"
+	ExportMovements  = "movements"
+
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatJSON = "json"
+
+	ExportJobPending   = "pending"
+	ExportJobRunning   = "running"
+	ExportJobCompleted = "completed"
+	ExportJobFailed    = "failed"
+)
+
+type ExportRequest struct {
+	Format    string   `json:"format" query:"format" validate:"omitempty,oneof=csv xlsx json"`
+	Category  string   `json:"category,omitempty" query:"category" validate:"omitempty,uuid"`
+	Brand     string   `json:"brand,omitempty" query:"brand" validate:"omitempty,max=100"`
+	Warehouse string   `json:"warehouse,omitempty" query:"warehouse" validate:"omitempty,uuid"`
+	Product   string   `json:"product,omitempty" query:"product" validate:"omitempty,uuid"`
+	Stock     string   `json:"stock,omitempty" query:"stock" validate:"omitempty,oneof=low out overstocked"`
+	MinPrice  *float64 `json:"minPrice,omitempty" query:"minPrice" validate:"omitempty,min=0"`
+	MaxPrice  *float64 `json:"maxPrice,omitempty" query:"maxPrice" validate:"omitempty,min=0"`
+	Reason    string   `json:"reason,omitempty" query:"reason" validate:"omitempty,oneof=receipt sale adjustment transfer damage count_correction"`
+	From      string   `json:"from,omitempty" query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To        string   `json:"to,omitempty" query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ExportQuery is a normalized export: which dataset, in which format, with which filters
+type ExportQuery struct {
+	InventoryID uuid.UUID      `json:"inventoryId"`
+	Type        string         `json:"type"`
+	Format      string         `json:"format"`
+	Products    *ProductFilter `json:"products,omitempty"`
+	WarehouseID *uuid.UUID     `json:"warehouseId,omitempty"`
+	ProductID   *uuid.UUID     `json:"productId,omitempty"`
+	Reason      string         `json:"reason,omitempty"`
+	From        *time.Time     `json:"from,omitempty"`
+	To          *time.Time     `json:"to,omitempty"`
+}
+
+type ExportJob struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	InventoryID uuid.UUID      `db:"inventory_id" json:"inventoryId"`
+	UserID      uuid.UUID      `db:"user_id" json:"userId"`
+	Type        string         `db:"type" json:"type"`
+	Format      string         `db:"format" json:"format"`
+	Status      string         `db:"status" json:"status"`
+	Query       types.JSONText `db:"query" json:"query"`
+	FilePath    *string        `db:"file_path" json:"-"`
+	RowCount    int            `db:"row_count" json:"rowCount"`
+	Error       *string        `db:"error" json:"error"`
+	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
+	CompletedAt *time.Time     `db:"completed_at" json:"completedAt"`
+	ExpiresAt   time.Time      `db:"expires_at" json:"expiresAt"`
+}
+
+type ExportJobResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Type        string     `json:"type"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	RowCount    int        `json:"rowCount"`
+	Error       *string    `json:"error"`
+	DownloadURL *string    `json:"downloadUrl"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+}
+
+// Export rows
+type ProductExportRow struct {
+	ID              uuid.UUID `db:"id"`
+	Name            string    `db:"name"`
+	SKU             *string   `db:"sku"`
+	Code            *string   `db:"code"`
+	Brand           *string   `db:"brand"`
+	Model           *string   `db:"model"`
+	Description     *string   `db:"description"`
+	Quantity        int       `db:"quantity"`
+	RestockLevel    int       `db:"restock_level"`
+	OptimalLevel    int       `db:"optimal_level"`
+	Cost            int       `db:"cost"`
+	Price           int       `db:"price"`
+	Categories      *string   `db:"categories"`
+	WarehouseStock  *string   `db:"warehouse_stock"`
+	PrimaryImageURL *string   `db:"primary_image_url"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+type WarehouseExportRow struct {
+	ID           uuid.UUID `db:"id"`
+	Name         string    `db:"name"`
+	Location     *string   `db:"location"`
+	Capacity     *int      `db:"capacity"`
+	ProductCount int       `db:"product_count"`
+	StockTotal   int       `db:"stock_total"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+type MovementExportRow struct {
+	ID            uuid.UUID  `db:"id"`
+	CreatedAt     time.Time  `db:"created_at"`
+	ProductID     *uuid.UUID `db:"product_id"`
+	ProductName   *string    `db:"product_name"`
+	ProductSKU    *string    `db:"product_sku"`
+	WarehouseID   *uuid.UUID `db:"warehouse_id"`
+	WarehouseName *string    `db:"warehouse_name"`
+	Delta         int        `db:"delta"`
+	Balance       int        `db:"balance"`
+	Reason        string     `db:"reason"`
+	Reference     *string    `db:"reference"`
+	Username      *string    `db:"username"`
+}
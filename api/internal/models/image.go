@@ -1,33 +1,120 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Image processing states
+const (
+	ImagePending    = "pending"
+	ImageProcessing = "processing"
+	ImageReady      = "ready"
+	ImageFailed     = "failed"
+)
+
 // Image Models
 type Image struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	URL       string    `db:"url" json:"url"`
-	FileKey   string    `db:"file_key" json:"fileKey"`
-	ProductId uuid.UUID `db:"product_id" json:"productId"`
-	IsPrimary bool      `db:"is_primary" json:"isPrimary"`
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	ID        uuid.UUID     `db:"id" json:"id"`
+	URL       string        `db:"url" json:"url"`
+	FileKey   string        `db:"file_key" json:"fileKey"`
+	ProductId uuid.UUID     `db:"product_id" json:"productId"`
+	IsPrimary bool          `db:"is_primary" json:"isPrimary"`
+	Position  int           `db:"position" json:"position"`
+	SizeBytes int64         `db:"size_bytes" json:"sizeBytes"`
+	Status    string        `db:"processing_status" json:"status"`
+	Variants  ImageVariants `db:"variants" json:"variants"`
+	Managed   bool          `db:"managed" json:"managed"` // the server stored the file at FileKey
+	CreatedAt time.Time     `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updatedAt"`
+}
+
+// ImageVariant is a resized copy of an image, stored under a key derived from the original
+type ImageVariant struct {
+	Name      string `json:"name"`
+	Format    string `json:"format"`
+	FileKey   string `json:"fileKey"`
+	URL       string `json:"url"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// ImageVariants is stored as a JSONB array
+type ImageVariants []ImageVariant
+
+func (v ImageVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(v)
+}
+
+func (v *ImageVariants) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("cannot scan %T into ImageVariants", src)
+	}
 }
 
+// ImageRequest refers to an existing image of the product in a product body. Files are only
+// added through the image upload endpoints, so storage keys are never taken from clients.
 type ImageRequest struct {
-	URL       string `json:"url" validate:"required"`
-	FileKey   string `json:"fileKey" validate:"required"`
+	ID        string `json:"id" validate:"required,uuid"`
 	IsPrimary bool   `json:"isPrimary"`
 }
 
 type ImageResponse struct {
-	ID        uuid.UUID `json:"id"`
-	URL       string    `json:"url"`
-	FileKey   string    `json:"fileKey"`
-	IsPrimary bool      `json:"isPrimary"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID        uuid.UUID      `json:"id"`
+	URL       string         `json:"url"`
+	FileKey   string         `json:"fileKey"`
+	IsPrimary bool           `json:"isPrimary"`
+	Position  int            `json:"position"`
+	Status    string         `json:"status"`
+	Variants  []ImageVariant `json:"variants"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// ImageOrderRequest lists every image of a product in the order it should be shown
+type ImageOrderRequest struct {
+	ImageIDs []string `json:"imageIds" validate:"required,min=1,dive,uuid"`
+}
+
+// ImageUpload is a presigned direct upload waiting for the client to confirm it
+type ImageUpload struct {
+	ID          uuid.UUID `db:"id"`
+	ProductId   uuid.UUID `db:"product_id"`
+	UserId      uuid.UUID `db:"user_id"`
+	FileKey     string    `db:"file_key"`
+	ContentType string    `db:"content_type"`
+	SizeBytes   int64     `db:"size_bytes"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+type ImageUploadRequest struct {
+	ContentType string `json:"contentType" validate:"required,oneof=image/jpeg image/png image/gif image/webp"`
+	Size        int64  `json:"size" validate:"required,min=1,max=5242880"`
+}
+
+// ImageUploadResponse tells the client where to PUT the file. Headers must be sent as given.
+type ImageUploadResponse struct {
+	UploadID  uuid.UUID         `json:"uploadId"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	FileKey   string            `json:"fileKey"`
+	ExpiresAt time.Time         `json:"expiresAt"`
 }
@@ -0,0 +1,36 @@
+package models
+
+import "github.com/google/uuid"
+
+// Product import models
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// ProductImportRow is a validated spreadsheet row ready to be written
+type ProductImportRow struct {
+	Row           int
+	Product       *Product
+	Existing      bool
+	CategoryNames []string
+	WarehouseIDs  []uuid.UUID
+}
+
+type ProductImportReport struct {
+	DryRun    bool                     `json:"dryRun"`
+	Committed bool                     `json:"committed"`
+	TotalRows int                      `json:"totalRows"`
+	ValidRows int                      `json:"validRows"`
+	Created   int                      `json:"created"`
+	Updated   int                      `json:"updated"`
+	Rows      []ProductImportRowResult `json:"rows"`
+}
+
+type ProductImportRowResult struct {
+	Row    int      `json:"row"`
+	SKU    string   `json:"sku"`
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Errors []string `json:"errors,omitempty"`
+}
@@ -7,11 +7,18 @@ import (
 )
 
 type Inventory struct {
-	Id        uuid.UUID `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	UserId    uuid.UUID `db:"user_id" json:"userId"`
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	Id               uuid.UUID `db:"id" json:"id"`
+	Name             string    `db:"name" json:"name"`
+	UserId           uuid.UUID `db:"user_id" json:"userId"`
+	RequireTwoFactor bool      `db:"require_two_factor" json:"requireTwoFactor"`
+	CreatedAt        time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// UserInventory is an inventory together with the role a user holds in it
+type UserInventory struct {
+	Inventory
+	Role string `db:"role" json:"role"`
 }
 
 type InventoryRequest struct {
@@ -19,9 +26,16 @@ type InventoryRequest struct {
 }
 
 type InventoryResponse struct {
-	Id        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	UserId    uuid.UUID `json:"userId"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	Id               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	UserId           uuid.UUID `json:"userId"`
+	Role             string    `json:"role,omitempty"`
+	RequireTwoFactor bool      `json:"requireTwoFactor"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// InventorySecurityRequest sets whether members must use two-factor authentication
+type InventorySecurityRequest struct {
+	RequireTwoFactor bool `json:"requireTwoFactor"`
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Inventory member roles, ordered from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Invitation statuses
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+type InventoryMember struct {
+	InventoryId uuid.UUID `db:"inventory_id" json:"inventoryId"`
+	UserId      uuid.UUID `db:"user_id" json:"userId"`
+	Role        string    `db:"role" json:"role"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type MemberDetails struct {
+	InventoryMember
+	Username string  `db:"username" json:"username"`
+	Email    string  `db:"email" json:"email"`
+	Avatar   *string `db:"avatar" json:"avatar"`
+}
+
+type MemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type MemberResponse struct {
+	UserId    uuid.UUID `json:"userId"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Avatar    *string   `json:"avatar"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Invitation struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	InventoryId uuid.UUID `db:"inventory_id" json:"inventoryId"`
+	Email       string    `db:"email" json:"email"`
+	Role        string    `db:"role" json:"role"`
+	TokenHash   string    `db:"token_hash" json:"-"`
+	Status      string    `db:"status" json:"status"`
+	InvitedBy   uuid.UUID `db:"invited_by" json:"invitedBy"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expiresAt"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+type InvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type InvitationTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type InvitationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	InvitedBy uuid.UUID `json:"invitedBy"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock movement reasons
+const (
+	ReasonReceipt         = "receipt"
+	ReasonSale            = "sale"
+	ReasonAdjustment      = "adjustment"
+	ReasonTransfer        = "transfer"
+	ReasonDamage          = "damage"
+	ReasonCountCorrection = "count_correction"
+)
+
+// StockMovement is a ledger entry. ProductID is nil once the product has been deleted, and
+// WarehouseID is nil for product-level changes or once the warehouse has been deleted.
+type StockMovement struct {
+	ID          uuid.UUID  `db:"id" json:"id"`
+	ProductID   *uuid.UUID `db:"product_id" json:"productId"`
+	WarehouseID *uuid.UUID `db:"warehouse_id" json:"warehouseId"`
+	InventoryID uuid.UUID  `db:"inventory_id" json:"inventoryId"`
+	Delta       int        `db:"delta" json:"delta"`
+	Balance     int        `db:"balance" json:"balance"`
+	Reason      string     `db:"reason" json:"reason"`
+	Reference   *string    `db:"reference" json:"reference"`
+	UserID      *uuid.UUID `db:"user_id" json:"userId"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// StockChange describes a quantity change to be written through the movement ledger.
+// A nil WarehouseID targets the product-level quantity.
+type StockChange struct {
+	ProductID   uuid.UUID
+	WarehouseID *uuid.UUID
+	Delta       int
+	Reason      string
+	Reference   *string
+	UserID      *uuid.UUID
+}
+
+type StockMovementResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ProductID   *uuid.UUID `json:"productId"`
+	WarehouseID *uuid.UUID `json:"warehouseId"`
+	Delta       int        `json:"delta"`
+	Balance     int        `json:"balance"`
+	Reason      string     `json:"reason"`
+	Reference   *string    `json:"reference"`
+	UserID      *uuid.UUID `json:"userId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type StockMovementPage struct {
+	Movements []StockMovementResponse `json:"movements"`
+	Total     int                     `json:"total"`
+	Page      int                     `json:"page"`
+	Limit     int                     `json:"limit"`
+}
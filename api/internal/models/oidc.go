@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider
+type UserIdentity struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"userId"`
+	Issuer      string    `db:"issuer" json:"issuer"`
+	Subject     string    `db:"subject" json:"subject"`
+	Email       string    `db:"email" json:"email"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	LastLoginAt time.Time `db:"last_login_at" json:"lastLoginAt"`
+}
+
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string    `json:"authorizationUrl"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// OIDCCallbackRequest carries the parameters the provider redirected back to the frontend with
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
@@ -63,3 +63,95 @@ type ProductResponse struct {
 	Categories   []CategoryResponse  `json:"categories"`
 	Warehouses   []WarehouseResponse `json:"warehouses"`
 }
+
+type WarehouseStock struct {
+	WarehouseID   uuid.UUID `db:"warehouse_id" json:"warehouseId"`
+	WarehouseName string    `db:"name" json:"warehouseName"`
+	Capacity      *int      `db:"capacity" json:"capacity"`
+	Quantity      int       `db:"quantity" json:"quantity"`
+}
+
+type StockRequest struct {
+	Quantity  int     `json:"quantity" validate:"min=0"`
+	Reason    string  `json:"reason" validate:"omitempty,oneof=receipt sale adjustment damage count_correction"`
+	Reference *string `json:"reference" validate:"omitempty,max=100"`
+}
+
+type StockAdjustmentRequest struct {
+	Quantity  int     `json:"quantity" validate:"required,min=1"`
+	Reason    string  `json:"reason" validate:"omitempty,oneof=receipt sale adjustment damage count_correction"`
+	Reference *string `json:"reference" validate:"omitempty,max=100"`
+}
+
+type ProductStockResponse struct {
+	ProductID  uuid.UUID                `json:"productId"`
+	Quantity   int                      `json:"quantity"`
+	Warehouses []WarehouseStockResponse `json:"warehouses"`
+}
+
+type WarehouseStockResponse struct {
+	WarehouseID   uuid.UUID `json:"warehouseId"`
+	WarehouseName string    `json:"warehouseName"`
+	Capacity      *int      `json:"capacity"`
+	Quantity      int       `json:"quantity"`
+}
+
+// Product list sorting and stock status filters
+const (
+	ProductSortName      = "name"
+	ProductSortSKU       = "sku"
+	ProductSortQuantity  = "quantity"
+	ProductSortPrice     = "price"
+	ProductSortCost      = "cost"
+	ProductSortCreatedAt = "createdAt"
+	ProductSortUpdatedAt = "updatedAt"
+
+	StockStatusLow         = "low"
+	StockStatusOut         = "out"
+	StockStatusOverstocked = "overstocked"
+)
+
+type ProductListRequest struct {
+	Cursor    string   `query:"cursor"`
+	Limit     int      `query:"limit" validate:"omitempty,min=1,max=200"`
+	Sort      string   `query:"sort" validate:"omitempty,oneof=name sku quantity price cost createdAt updatedAt"`
+	Order     string   `query:"order" validate:"omitempty,oneof=asc desc"`
+	Category  string   `query:"category" validate:"omitempty,uuid"`
+	Brand     string   `query:"brand" validate:"omitempty,max=100"`
+	Warehouse string   `query:"warehouse" validate:"omitempty,uuid"`
+	MinPrice  *float64 `query:"minPrice" validate:"omitempty,min=0"`
+	MaxPrice  *float64 `query:"maxPrice" validate:"omitempty,min=0"`
+	MinCost   *float64 `query:"minCost" validate:"omitempty,min=0"`
+	MaxCost   *float64 `query:"maxCost" validate:"omitempty,min=0"`
+	Stock     string   `query:"stock" validate:"omitempty,oneof=low out overstocked"`
+}
+
+// ProductFilter is the normalized list query; prices and costs are in cents
+type ProductFilter struct {
+	InventoryID uuid.UUID  `json:"inventoryId"`
+	Cursor      string     `json:"cursor"`
+	Limit       int        `json:"limit"`
+	Sort        string     `json:"sort"`
+	Order       string     `json:"order"`
+	CategoryID  *uuid.UUID `json:"categoryId"`
+	Brand       string     `json:"brand"`
+	WarehouseID *uuid.UUID `json:"warehouseId"`
+	MinPrice    *int       `json:"minPrice"`
+	MaxPrice    *int       `json:"maxPrice"`
+	MinCost     *int       `json:"minCost"`
+	MaxCost     *int       `json:"maxCost"`
+	Stock       string     `json:"stock"`
+}
+
+type ProductList struct {
+	Products   []Product `json:"products"`
+	Total      int       `json:"total"`
+	NextCursor *string   `json:"nextCursor"`
+}
+
+type ProductPage struct {
+	Products   []ProductResponse `json:"products"`
+	Total      int               `json:"total"`
+	Limit      int               `json:"limit"`
+	NextCursor *string           `json:"nextCursor"`
+}
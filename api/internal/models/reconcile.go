@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StoredImageObject is an object key referenced by a product image, either its original or
+// one of its variants
+type StoredImageObject struct {
+	ImageID   uuid.UUID `db:"image_id" json:"imageId"`
+	ProductID uuid.UUID `db:"product_id" json:"productId"`
+	FileKey   string    `db:"file_key" json:"fileKey"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// OrphanedObject is a bucket object no product image or pending upload refers to
+type OrphanedObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	Deleted      bool      `json:"deleted"`
+}
+
+// ReconcileReport is the outcome of comparing the bucket with the database
+type ReconcileReport struct {
+	StartedAt      time.Time           `json:"startedAt"`
+	FinishedAt     time.Time           `json:"finishedAt"`
+	DryRun         bool                `json:"dryRun"`
+	ObjectsScanned int                 `json:"objectsScanned"`
+	ReferencedKeys int                 `json:"referencedKeys"`
+	Orphans        []OrphanedObject    `json:"orphans"`
+	OrphansDeleted int                 `json:"orphansDeleted"`
+	OrphansInGrace int                 `json:"orphansInGrace"`
+	Missing        []StoredImageObject `json:"missing"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// Product search models
+type ProductSearchRequest struct {
+	Query string `query:"q" validate:"required,min=1,max=100"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ProductSearchHit struct {
+	Product
+	ExactMatch           bool    `db:"exact_match"`
+	Score                float64 `db:"score"`
+	NameHighlight        *string `db:"name_highlight"`
+	BrandHighlight       *string `db:"brand_highlight"`
+	ModelHighlight       *string `db:"model_highlight"`
+	DescriptionHighlight *string `db:"description_highlight"`
+	CategoryNames        *string `db:"category_names"`
+	CategoryHighlight    *string `db:"category_highlight"`
+}
+
+// ProductSearchResult carries highlights keyed by field. They are HTML fragments: the field's
+// text escaped, with matches wrapped in <mark> tags.
+type ProductSearchResult struct {
+	Product    ProductResponse   `json:"product"`
+	Score      float64           `json:"score"`
+	ExactMatch bool              `json:"exactMatch"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type ProductSearchResponse struct {
+	Query   string                `json:"query"`
+	Results []ProductSearchResult `json:"results"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session revocation reasons
+const (
+	RevokedLogout = "logout"
+	RevokedReuse  = "refresh_token_reuse"
+	RevokedReset  = "password_reset"
+
+	RevokedPasswordChange = "password_change"
+	RevokedAccountDeleted = "account_deleted"
+	RevokedTerminated     = "terminated"
+	RevokedSuspended      = "account_suspended"
+)
+
+// AuthSession is one login. Every refresh token rotated from that login belongs to it, so
+// revoking the session invalidates the whole token family.
+type AuthSession struct {
+	ID             uuid.UUID  `db:"id" json:"id"`
+	UserID         uuid.UUID  `db:"user_id" json:"userId"`
+	UserAgent      string     `db:"user_agent" json:"userAgent"`
+	IPAddress      string     `db:"ip_address" json:"ipAddress"`
+	ImpersonatorID *uuid.UUID `db:"impersonator_id" json:"impersonatorId"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	LastUsedAt     time.Time  `db:"last_used_at" json:"lastUsedAt"`
+	ExpiresAt      time.Time  `db:"expires_at" json:"expiresAt"`
+	RevokedAt      *time.Time `db:"revoked_at" json:"revokedAt"`
+	RevokedReason  *string    `db:"revoked_reason" json:"revokedReason"`
+}
+
+// ClientInfo describes the device a login came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionResponse is a signed-in device as shown to its user
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+	// Opened by a system administrator acting as the user
+	Impersonated bool `json:"impersonated"`
+}
+
+// SessionClaims identifies the session behind a verified access token
+type SessionClaims struct {
+	SessionID      uuid.UUID
+	ImpersonatorID *uuid.UUID
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	SessionID uuid.UUID  `db:"session_id" json:"sessionId"`
+	TokenHash string     `db:"token_hash" json:"-"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expiresAt"`
+	UsedAt    *time.Time `db:"used_at" json:"usedAt"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type TokenResponse struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transfer statuses
+const (
+	TransferInTransit = "in_transit"
+	TransferCompleted = "completed"
+	TransferCancelled = "cancelled"
+)
+
+type Transfer struct {
+	ID                     uuid.UUID      `db:"id" json:"id"`
+	InventoryID            uuid.UUID      `db:"inventory_id" json:"inventoryId"`
+	SourceWarehouseID      uuid.UUID      `db:"source_warehouse_id" json:"sourceWarehouseId"`
+	DestinationWarehouseID uuid.UUID      `db:"destination_warehouse_id" json:"destinationWarehouseId"`
+	Status                 string         `db:"status" json:"status"`
+	Reference              *string        `db:"reference" json:"reference"`
+	CreatedBy              *uuid.UUID     `db:"created_by" json:"createdBy"`
+	ReceivedBy             *uuid.UUID     `db:"received_by" json:"receivedBy"`
+	ShippedAt              time.Time      `db:"shipped_at" json:"shippedAt"`
+	ReceivedAt             *time.Time     `db:"received_at" json:"receivedAt"`
+	CreatedAt              time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt              time.Time      `db:"updated_at" json:"updatedAt"`
+	Items                  []TransferItem `json:"items"`
+}
+
+type TransferItem struct {
+	TransferID uuid.UUID `db:"transfer_id" json:"transferId"`
+	ProductID  uuid.UUID `db:"product_id" json:"productId"`
+	Quantity   int       `db:"quantity" json:"quantity"`
+}
+
+type TransferRequest struct {
+	SourceWarehouseID      string                `json:"sourceWarehouseId" validate:"required,uuid"`
+	DestinationWarehouseID string                `json:"destinationWarehouseId" validate:"required,uuid"`
+	Reference              *string               `json:"reference" validate:"omitempty,max=100"`
+	Ship                   bool                  `json:"ship"` // Leave stock in transit until received
+	Items                  []TransferItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type TransferItemRequest struct {
+	ProductID string `json:"productId" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+}
+
+type TransferResponse struct {
+	ID                     uuid.UUID              `json:"id"`
+	SourceWarehouseID      uuid.UUID              `json:"sourceWarehouseId"`
+	DestinationWarehouseID uuid.UUID              `json:"destinationWarehouseId"`
+	Status                 string                 `json:"status"`
+	Reference              *string                `json:"reference"`
+	CreatedBy              *uuid.UUID             `json:"createdBy"`
+	ReceivedBy             *uuid.UUID             `json:"receivedBy"`
+	ShippedAt              time.Time              `json:"shippedAt"`
+	ReceivedAt             *time.Time             `json:"receivedAt"`
+	CreatedAt              time.Time              `json:"createdAt"`
+	UpdatedAt              time.Time              `json:"updatedAt"`
+	Items                  []TransferItemResponse `json:"items"`
+}
+
+type TransferItemResponse struct {
+	ProductID uuid.UUID `json:"productId"`
+	Quantity  int       `json:"quantity"`
+}
@@ -8,14 +8,18 @@ import (
 )
 
 type User struct {
-	Id        uuid.UUID `db:"id" json:"id"`
-	Username  string    `db:"username" json:"username"`
-	Email     string    `db:"email" json:"email"`
-	Password  string    `db:"password" json:"-"`
-	Role      string    `db:"role" json:"role"`
-	Avatar    *string   `db:"avatar" json:"avatar"`
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	Id              uuid.UUID  `db:"id" json:"id"`
+	Username        string     `db:"username" json:"username"`
+	Email           string     `db:"email" json:"email"`
+	Password        string     `db:"password" json:"-"`
+	Role            string     `db:"role" json:"role"`
+	Avatar          *string    `db:"avatar" json:"avatar"`
+	AvatarKey       *string    `db:"avatar_key" json:"-"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"emailVerifiedAt"`
+	SuspendedAt     *time.Time `db:"suspended_at" json:"suspendedAt"`
+	SuspendedReason *string    `db:"suspended_reason" json:"-"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updatedAt"`
 }
 
 type UserRequest struct {
@@ -27,12 +31,14 @@ type UserRequest struct {
 }
 
 type UserResponse struct {
-	UserId      string      `json:"userId"`
-	UserName    string      `json:"userName"`
-	Email       string      `json:"email"`
-	Avatar      *string     `json:"avatar"`
-	Token       string      `json:"token"`
-	Inventories []Inventory `json:"inventories"`
+	UserId         string          `json:"userId"`
+	UserName       string          `json:"userName"`
+	Email          string          `json:"email"`
+	Avatar         *string         `json:"avatar"`
+	Token          string          `json:"token"`
+	TokenExpiresAt time.Time       `json:"tokenExpiresAt"`
+	RefreshToken   string          `json:"refreshToken"`
+	Inventories    []UserInventory `json:"inventories"`
 }
 
 func (req *UserRequest) Sanitize() {
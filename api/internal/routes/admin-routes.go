@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/admin"
+	"github.com/venntry/internal/features/account/auth"
+)
+
+func AdminRoutes(e *echo.Echo, ac admin.IAdminController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore) {
+	api := e.Group("/api/admin")
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "admin"), auth.RequireSession(), auth.RoleMiddleware("admin"))
+
+	api.GET("/users", ac.ListUsers)
+	api.GET("/users/:id", ac.GetUser)
+	api.GET("/users/:id/inventories", ac.ListUserInventories)
+	api.POST("/users/:id/suspend", ac.SuspendUser)
+	api.POST("/users/:id/reactivate", ac.ReactivateUser)
+	api.POST("/users/:id/reset-password", ac.ResetPassword)
+	api.POST("/users/:id/unlock", ac.UnlockUser)
+	api.POST("/users/:id/impersonate", ac.Impersonate)
+
+	api.GET("/audit-log", ac.ListAuditLog)
+}
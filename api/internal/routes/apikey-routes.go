@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/apikeys"
+	"github.com/venntry/internal/features/account/auth"
+)
+
+func APIKeyRoutes(e *echo.Echo, kc apikeys.IAPIKeyController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore) {
+	// Keys are managed with a signed-in session only, never with another key
+	api := e.Group("/api/auth/api-keys")
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "account"), auth.RequireSession())
+
+	api.GET("", kc.ListKeys)
+	api.POST("", kc.CreateKey)
+	api.POST("/:keyId/rotate", kc.RotateKey)
+	api.DELETE("/:keyId", kc.RevokeKey)
+}
@@ -2,16 +2,35 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/venntry/internal/features/account/auth"
 )
 
-func AuthRoutes(e *echo.Echo, ac auth.IAuthController, authService auth.IAuthService) {
+func AuthRoutes(e *echo.Echo, ac auth.IAuthController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore) {
 	api := e.Group("/api/auth")
+	api.Use(auth.RateLimitMiddleware(rateLimiter, "auth"))
 	api.POST("/register", ac.Register)
 	api.POST("/login", ac.Login)
+	api.POST("/login/2fa", ac.LoginTwoFactor)
+	api.GET("/oidc/authorize", ac.OIDCAuthorize)
+	api.POST("/oidc/callback", ac.OIDCCallback)
+	api.POST("/refresh", ac.RefreshToken)
+	api.POST("/forgot-password", ac.ForgotPassword)
+	api.POST("/reset-password", ac.ResetPassword)
+	api.POST("/verify-email", ac.VerifyEmail)
+	api.POST("/unlock-account", ac.UnlockAccount)
 
 	protected := e.Group("/api/auth")
-	protected.Use(auth.AuthMiddleware(authService))
+	protected.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "auth"), auth.RequireSession())
 	protected.GET("/user-profile", ac.GetProfile)
 	protected.GET("/check-token", ac.CheckTokenExpiration)
+	protected.POST("/logout", ac.Logout)
+	protected.GET("/sessions", ac.ListSessions)
+	protected.DELETE("/sessions/:id", ac.TerminateSession)
+	protected.POST("/resend-verification", ac.ResendVerification)
+
+	protected.POST("/2fa/setup", ac.SetupTwoFactor)
+	protected.POST("/2fa/enable", ac.EnableTwoFactor)
+	protected.POST("/2fa/disable", ac.DisableTwoFactor)
+	protected.POST("/2fa/recovery-codes", ac.RegenerateRecoveryCodes)
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/features/app/exports"
+)
+
+func ExportRoutes(e *echo.Echo, ec exports.IExportController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
+	api := e.Group("/api/inventories/:inventoryId/exports")
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "exports"), auth.RoleMiddleware("user"))
+	api.Use(access.InventoryMiddleware(accessService, access.FromInventory("inventoryId")))
+
+	// Background jobs for large exports
+	api.GET("/jobs", ec.ListExportJobs)
+	api.GET("/jobs/:jobId", ec.GetExportJob)
+	api.GET("/jobs/:jobId/download", ec.DownloadExportJob)
+
+	// Streamed exports of products, warehouses or movements
+	api.GET("/:type", ec.ExportData)
+	api.POST("/:type/jobs", ec.CreateExportJob)
+}
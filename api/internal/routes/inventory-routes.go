@@ -2,17 +2,26 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
 	"github.com/venntry/internal/features/account/auth"
 	"github.com/venntry/internal/features/account/inventories"
+	"github.com/venntry/internal/models"
 )
 
-func InventoryRoutes(e *echo.Echo, ic inventories.IInventoriesController, authService auth.IAuthService) {
+func InventoryRoutes(e *echo.Echo, ic inventories.IInventoriesController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
 	api := e.Group("/api/inventories")
-	api.Use(auth.AuthMiddleware(authService), auth.RoleMiddleware("user"))
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "inventories"), auth.RoleMiddleware("user"))
 
-	api.GET("", ic.ListInventories)
-	api.GET("/:id", ic.GetInventory)
-	api.POST("", ic.CreateInventory)
-	api.PUT("/:id", ic.UpdateInventory)
-	api.DELETE("/:id", ic.DeleteInventory)
+	api.GET("", ic.ListInventories, auth.RejectInventoryKeys())
+	api.POST("", ic.CreateInventory, auth.RejectInventoryKeys())
+
+	// Routes scoped to a single inventory
+	inventoryAccess := access.InventoryMiddleware(accessService, access.FromInventory("id"))
+
+	api.GET("/:id", ic.GetInventory, inventoryAccess)
+	api.PUT("/:id", ic.UpdateInventory, inventoryAccess, access.RequireInventoryRole(models.RoleAdmin))
+	api.PATCH("/:id", ic.PatchInventory, inventoryAccess, access.RequireInventoryRole(models.RoleAdmin))
+	api.DELETE("/:id", ic.DeleteInventory, inventoryAccess, access.RequireInventoryRole(models.RoleOwner))
+	api.PUT("/:id/security", ic.UpdateSecurity, inventoryAccess, access.RequireInventoryRole(models.RoleOwner))
 }
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/features/account/members"
+	"github.com/venntry/internal/models"
+)
+
+func MemberRoutes(e *echo.Echo, mc members.IMembersController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
+	// Invitation responses come from users who are not members yet
+	invitee := e.Group("/api/inventories/:id/members/invitations")
+	invitee.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "members"), auth.RequireSession(), auth.RoleMiddleware("user"))
+
+	invitee.POST("/accept", mc.AcceptInvitation)
+	invitee.POST("/decline", mc.DeclineInvitation)
+
+	// Routes for managing an inventory's team
+	api := e.Group("/api/inventories/:id/members")
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "members"), auth.RoleMiddleware("user"))
+	api.Use(access.InventoryMiddleware(accessService, access.FromInventory("id")))
+
+	manage := access.RequireInventoryRole(models.RoleAdmin)
+
+	api.GET("", mc.ListMembers)
+	api.PATCH("/:userId", mc.UpdateMemberRole, manage)
+	api.DELETE("/:userId", mc.RemoveMember)
+
+	api.GET("/invitations", mc.ListInvitations, manage)
+	api.POST("/invitations", mc.InviteMember, manage)
+	api.DELETE("/invitations/:invitationId", mc.RevokeInvitation, manage)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/features/app/movements"
+)
+
+func MovementRoutes(e *echo.Echo, mc movements.IMovementController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
+	// Stock movement history per product
+	productApi := e.Group("/api/products/:productId/movements")
+	productApi.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "movements"), auth.RoleMiddleware("user"))
+	productApi.Use(access.InventoryMiddleware(accessService, access.FromProduct("productId")))
+
+	productApi.GET("", mc.ListProductMovements)
+
+	// Stock movement history per warehouse
+	warehouseApi := e.Group("/api/warehouses/:id/movements")
+	warehouseApi.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "movements"), auth.RoleMiddleware("user"))
+	warehouseApi.Use(access.InventoryMiddleware(accessService, access.FromWarehouse("id")))
+
+	warehouseApi.GET("", mc.ListWarehouseMovements)
+}
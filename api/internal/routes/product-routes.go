@@ -2,25 +2,50 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
 	"github.com/venntry/internal/features/account/auth"
 	"github.com/venntry/internal/features/app/products"
+	"github.com/venntry/internal/models"
 )
 
-func ProductRoutes(e *echo.Echo, pc products.IProductsController, authService auth.IAuthService) {
+func ProductRoutes(e *echo.Echo, pc products.IProductsController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
 	// Routes for listing and creating products
 	api := e.Group("/api/inventories/:inventoryId")
-	api.Use(auth.AuthMiddleware(authService), auth.RoleMiddleware("user"))
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "products"), auth.RoleMiddleware("user"))
+	api.Use(access.InventoryMiddleware(accessService, access.FromInventory("inventoryId")))
+
+	edit := access.RequireInventoryRole(models.RoleEditor)
 
 	api.GET("/products", pc.ListProducts)
+	api.GET("/products/search", pc.SearchProducts)
 	api.GET("/categories", pc.ListProductCategories)
-	api.POST("/products", pc.CreateProduct)
+	api.POST("/products", pc.CreateProduct, edit)
+	api.POST("/products/import", pc.ImportProducts, edit)
 
 	// Routes for individual product operations
 	productApi := e.Group("/api/products/:productId")
-	productApi.Use(auth.AuthMiddleware(authService), auth.RoleMiddleware("user"))
+	productApi.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "products"), auth.RoleMiddleware("user"))
+	productApi.Use(access.InventoryMiddleware(accessService, access.FromProduct("productId")))
 
 	productApi.GET("", pc.GetProductByID)
-	productApi.PUT("", pc.UpdateProduct)
-	productApi.DELETE("", pc.DeleteProduct)
-	// productApi.PATCH("/quantity", pc.UpdateProductQuantity)
+	productApi.PUT("", pc.UpdateProduct, edit)
+	productApi.PATCH("", pc.PatchProduct, edit)
+	productApi.DELETE("", pc.DeleteProduct, edit)
+
+	// Per-warehouse stock management
+	productApi.GET("/stock", pc.GetProductStock)
+	productApi.PUT("/stock/:warehouseId", pc.SetWarehouseStock, edit)
+	productApi.POST("/stock/:warehouseId/increment", pc.IncrementWarehouseStock, edit)
+	productApi.POST("/stock/:warehouseId/decrement", pc.DecrementWarehouseStock, edit)
+
+	// Product images, stored in the object bucket
+	productApi.POST("/images", pc.UploadProductImages, edit)
+	productApi.PUT("/images/order", pc.ReorderProductImages, edit)
+	productApi.PUT("/images/:imageId/primary", pc.SetPrimaryProductImage, edit)
+	productApi.DELETE("/images/:imageId", pc.DeleteProductImage, edit)
+
+	// Direct uploads: the client PUTs the file to a signed URL, then confirms it
+	productApi.POST("/images/uploads", pc.CreateImageUpload, edit)
+	productApi.POST("/images/uploads/:uploadId/confirm", pc.ConfirmImageUpload, edit)
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/features/account/profile"
+)
+
+func ProfileRoutes(e *echo.Echo, pc profile.IProfileController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore) {
+	api := e.Group("/api/auth/user-profile")
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "account"), auth.RequireSession())
+
+	api.PATCH("", pc.UpdateProfile)
+	api.DELETE("", pc.DeleteAccount)
+	api.PUT("/password", pc.ChangePassword)
+	api.PUT("/avatar", pc.UploadAvatar)
+	api.DELETE("/avatar", pc.RemoveAvatar)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
+	"github.com/venntry/internal/features/account/auth"
+	"github.com/venntry/internal/features/app/transfers"
+	"github.com/venntry/internal/models"
+)
+
+func TransferRoutes(e *echo.Echo, tc transfers.ITransferController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
+	api := e.Group("/api/inventories/:inventoryId/transfers")
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "transfers"), auth.RoleMiddleware("user"))
+	api.Use(access.InventoryMiddleware(accessService, access.FromInventory("inventoryId")))
+
+	edit := access.RequireInventoryRole(models.RoleEditor)
+
+	api.GET("", tc.ListTransfers)
+	api.GET("/:transferId", tc.GetTransfer)
+	api.POST("", tc.CreateTransfer, edit)
+	api.POST("/:transferId/receive", tc.ReceiveTransfer, edit)
+	api.POST("/:transferId/cancel", tc.CancelTransfer, edit)
+}
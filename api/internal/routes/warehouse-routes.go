@@ -2,24 +2,32 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/venntry/internal/features/account/access"
 	"github.com/venntry/internal/features/account/auth"
 	"github.com/venntry/internal/features/app/warehouses"
+	"github.com/venntry/internal/models"
 )
 
-func WarehouseRoutes(e *echo.Echo, wc warehouses.IWarehouseController, authService auth.IAuthService) {
+func WarehouseRoutes(e *echo.Echo, wc warehouses.IWarehouseController, authService auth.IAuthService, rateLimiter middleware.RateLimiterStore, accessService access.IAccessService) {
 	// Routes for listing and creating warehouses
 	api := e.Group("/api/inventories/:inventoryId/warehouses")
-	api.Use(auth.AuthMiddleware(authService), auth.RoleMiddleware("user"))
+	api.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "warehouses"), auth.RoleMiddleware("user"))
+	api.Use(access.InventoryMiddleware(accessService, access.FromInventory("inventoryId")))
+
+	edit := access.RequireInventoryRole(models.RoleEditor)
 
 	api.GET("", wc.ListWarehouses)
-	api.POST("", wc.CreateWarehouse)
+	api.POST("", wc.CreateWarehouse, edit)
 
 	// Routes for individual warehouse operations
 	warehouseApi := e.Group("/api/warehouses/:id")
-	warehouseApi.Use(auth.AuthMiddleware(authService), auth.RoleMiddleware("user"))
+	warehouseApi.Use(auth.AuthMiddleware(authService), auth.RateLimitMiddleware(rateLimiter, "warehouses"), auth.RoleMiddleware("user"))
+	warehouseApi.Use(access.InventoryMiddleware(accessService, access.FromWarehouse("id")))
 
 	warehouseApi.GET("", wc.GetWarehouse)
-	warehouseApi.PUT("", wc.UpdateWarehouse)
-	warehouseApi.DELETE("", wc.DeleteWarehouse)
+	warehouseApi.PUT("", wc.UpdateWarehouse, edit)
+	warehouseApi.PATCH("", wc.PatchWarehouse, edit)
+	warehouseApi.DELETE("", wc.DeleteWarehouse, edit)
 
 }
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	type position struct {
+		Value string `json:"v"`
+		ID    string `json:"id"`
+	}
+	in := position{Value: "Widget & Co?/+", ID: "0b7c7bbd-6f7a-4e0c-9d0e-1f1d7a5c9b11"}
+
+	cursor, err := EncodeCursor(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(cursor, "+/=") {
+		t.Errorf("cursor %q is not URL safe", cursor)
+	}
+
+	var out position
+	if err := DecodeCursor(cursor, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("DecodeCursor = %+v, want %+v", out, in)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	var out map[string]interface{}
+	for _, cursor := range []string{"not base64!", "bm90IGpzb24"} {
+		if err := DecodeCursor(cursor, &out); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded", cursor)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/venntry/pkg/errors"
+	"github.com/venntry/pkg/logger"
+)
+
+// BindMergePatch applies the request body to target as an RFC 7396 JSON merge patch and
+// validates only the fields the patch touched. target must be a pointer to a struct that
+// already holds the current state of the resource. The touched JSON field names are returned.
+func BindMergePatch(ctx echo.Context, target interface{}) ([]string, error) {
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.BadRequest, "Failed to read request body", 400)
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, errors.ValidationError("Merge patch must be a JSON object")
+	}
+
+	current, err := json.Marshal(target)
+	if err != nil {
+		return nil, errors.InternalError(err, "Failed to encode current resource")
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(current, &document); err != nil {
+		return nil, errors.InternalError(err, "Failed to decode current resource")
+	}
+
+	merged, err := json.Marshal(MergePatch(document, patch))
+	if err != nil {
+		return nil, errors.InternalError(err, "Failed to apply merge patch")
+	}
+
+	// Reset target so fields removed by the patch fall back to their zero values
+	reflect.ValueOf(target).Elem().Set(reflect.Zero(reflect.TypeOf(target).Elem()))
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return nil, errors.Wrap(err, errors.BadRequest, "Failed to parse merge patch", 400)
+	}
+
+	touched := make([]string, 0, len(patch))
+	for key := range patch {
+		touched = append(touched, key)
+	}
+
+	if err := validatePartial(ctx, target, touched); err != nil {
+		logger.Error(ctx, "Input validation failed", err,
+			logger.Field("path", ctx.Path()),
+			logger.Field("method", ctx.Request().Method),
+			logger.Field("fields", touched),
+		)
+		return nil, errors.Wrap(err, errors.ValidationErr, "Input validation failed", 400)
+	}
+
+	return touched, nil
+}
+
+// MergePatch merges patch into target following RFC 7396: null removes a member, objects
+// merge recursively and every other value replaces the target member.
+func MergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			targetObject, _ := target[key].(map[string]interface{})
+			target[key] = MergePatch(targetObject, patchObject)
+			continue
+		}
+
+		target[key] = value
+	}
+
+	return target
+}
+
+// validatePartial runs struct validation restricted to the given JSON field names
+func validatePartial(ctx echo.Context, target interface{}, jsonFields []string) error {
+	cv, ok := ctx.Echo().Validator.(*CustomValidator)
+	if !ok {
+		return ctx.Validate(target)
+	}
+
+	structType := reflect.TypeOf(target).Elem()
+	fields := make([]string, 0, len(jsonFields))
+	for _, name := range jsonFields {
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if strings.Split(field.Tag.Get("json"), ",")[0] == name {
+				fields = append(fields, field.Name)
+				break
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return cv.Validator.StructPartial(target, fields...)
+}
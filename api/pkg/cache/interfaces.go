@@ -9,8 +9,11 @@ import (
 
 type IRedisService interface {
 	Get(key string, dest interface{}) error
+	// GetDel reads and removes a key in one step, so only one caller can consume it
+	GetDel(key string, dest interface{}) error
 	Set(key string, value interface{}, expiration time.Duration) error
 	Delete(key string) error
+	Increment(key string, expiration time.Duration) (int64, error)
 }
 
 type RedisCache struct {
@@ -42,6 +42,17 @@ func (r *RedisCache) Get(key string, dest interface{}) error {
 	return json.Unmarshal(val, dest)
 }
 
+func (r *RedisCache) GetDel(key string, dest interface{}) error {
+	val, err := r.client.GetDel(r.ctx, key).Bytes()
+	if err == redis.Nil {
+		return ErrCacheMiss
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(val, dest)
+}
+
 func (r *RedisCache) Set(key string, value interface{}, expiration time.Duration) error {
 	jsonData, err := json.Marshal(value)
 	if err != nil {
@@ -55,6 +66,23 @@ func (r *RedisCache) Delete(key string) error {
 	return r.client.Del(r.ctx, key).Err()
 }
 
+// Increment atomically adds one to a counter. The expiration starts with the first
+// increment, so the counter covers a fixed window.
+func (r *RedisCache) Increment(key string, expiration time.Duration) (int64, error) {
+	count, err := r.client.Incr(r.ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 1 {
+		if err := r.client.Expire(r.ctx, key, expiration).Err(); err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
 func (r *RedisCache) Close() error {
 	return r.client.Close()
 }
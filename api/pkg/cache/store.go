@@ -6,13 +6,16 @@ import (
 	"github.com/go-redis/redis_rate/v10"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/redis/go-redis/v9"
+	"github.com/venntry/pkg/logger"
 )
 
 type rateLimiterStore struct {
 	limiter *redis_rate.Limiter
+	limit   redis_rate.Limit
 }
 
-func NewRateLimiterStore(redisURL string) middleware.RateLimiterStore {
+// NewRateLimiterStore returns a Redis-backed store allowing perMinute requests per identifier
+func NewRateLimiterStore(redisURL string, perMinute int) middleware.RateLimiterStore {
 	opt, err := redis.ParseURL(redisURL)
 	if err != nil {
 		panic(err)
@@ -23,13 +26,16 @@ func NewRateLimiterStore(redisURL string) middleware.RateLimiterStore {
 
 	return &rateLimiterStore{
 		limiter: limiter,
+		limit:   redis_rate.PerMinute(perMinute),
 	}
 }
 
 func (store *rateLimiterStore) Allow(identifier string) (bool, error) {
-	res, err := store.limiter.Allow(context.Background(), identifier, redis_rate.PerMinute(60))
+	res, err := store.limiter.Allow(context.Background(), identifier, store.limit)
 	if err != nil {
-		return false, err
+		// Fail open: a Redis outage should not take the whole API down with it
+		logger.LogError("Rate limiter unavailable", err, logger.Field("identifier", identifier))
+		return true, nil
 	}
 	return res.Allowed > 0, nil
 }
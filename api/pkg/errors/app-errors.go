@@ -17,6 +17,7 @@ const (
 	Forbidden     ErrorType = "FORBIDDEN"
 	DatabaseErr   ErrorType = "DATABASE_ERROR"
 	ConflictErr   ErrorType = "CONFLICT"
+	TooManyReqs   ErrorType = "TOO_MANY_REQUESTS"
 )
 
 // Internal representation of the error
@@ -150,6 +151,10 @@ func ConflictError(message string) *AppError {
 	return New(ConflictErr, message, 409)
 }
 
+func TooManyRequestsError(message string) *AppError {
+	return New(TooManyReqs, message, 429)
+}
+
 func CacheError(err error, message string) *AppError {
 	return Wrap(err, InternalErr, message, 500)
 }
@@ -0,0 +1,154 @@
+// Package imageproc prepares uploaded photos for serving: it strips metadata, applies the
+// EXIF orientation and renders resized variants in a classic format and WebP.
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/gen2brain/webp"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+
+	// Larger images are refused before decoding, to bound memory use
+	maxPixels = 40_000_000
+
+	jpegQuality = 85
+	webpQuality = 80
+	webpMethod  = 4
+)
+
+var ErrUnsupported = errors.New("unsupported image")
+
+// Size is a variant fitted inside a MaxDimension square, never upscaled
+type Size struct {
+	Name         string
+	MaxDimension int
+}
+
+var Sizes = []Size{
+	{Name: "thumbnail", MaxDimension: 200},
+	{Name: "medium", MaxDimension: 800},
+	{Name: "large", MaxDimension: 1600},
+}
+
+// Encoded is one rendered image
+type Encoded struct {
+	Name   string
+	Format string
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Ext returns the file extension for the encoded format
+func (e *Encoded) Ext() string {
+	if e.Format == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + e.Format
+}
+
+type Result struct {
+	// Original is the full-size image re-encoded without metadata. It is nil for GIFs, which
+	// carry no EXIF data and would lose their animation.
+	Original *Encoded
+	Variants []Encoded
+}
+
+// Process decodes a JPEG, PNG, GIF or WebP image and renders every size in Sizes twice: as
+// WebP, and as JPEG or, when the image has transparency, PNG
+func Process(data []byte) (*Result, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds the pixel limit", ErrUnsupported, config.Width, config.Height)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	result := &Result{}
+	if format != FormatGIF {
+		original, err := encode("original", img, format)
+		if err != nil {
+			return nil, err
+		}
+		result.Original = original
+	}
+
+	fallback := FormatJPEG
+	if !isOpaque(img) {
+		fallback = FormatPNG
+	}
+
+	for _, size := range Sizes {
+		resized := imaging.Fit(img, size.MaxDimension, size.MaxDimension, imaging.Lanczos)
+		for _, format := range []string{fallback, FormatWebP} {
+			variant, err := encode(size.Name, resized, format)
+			if err != nil {
+				return nil, err
+			}
+			result.Variants = append(result.Variants, *variant)
+		}
+	}
+
+	return result, nil
+}
+
+// VariantKey derives the storage key of a variant from the key of its original
+func VariantKey(fileKey, name, ext string) string {
+	return strings.TrimSuffix(fileKey, path.Ext(fileKey)) + "_" + name + ext
+}
+
+// encode writes img in format; nothing but the pixels is carried over
+func encode(name string, img image.Image, format string) (*Encoded, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	case FormatWebP:
+		err = webp.Encode(&buf, img, webp.Options{Quality: webpQuality, Method: webpMethod})
+	default:
+		return nil, fmt.Errorf("%w: cannot encode %s", ErrUnsupported, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s %s: %w", name, format, err)
+	}
+
+	bounds := img.Bounds()
+	return &Encoded{
+		Name:   name,
+		Format: format,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		Data:   buf.Bytes(),
+	}, nil
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
+}
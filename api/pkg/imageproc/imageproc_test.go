@@ -0,0 +1,70 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestVariantKey(t *testing.T) {
+	tests := []struct {
+		fileKey string
+		name    string
+		ext     string
+		want    string
+	}{
+		{"products/inv/abc.jpg", "thumbnail", ".webp", "products/inv/abc_thumbnail.webp"},
+		{"products/inv/abc.jpeg", "medium", ".jpg", "products/inv/abc_medium.jpg"},
+		{"products/inv/abc", "large", ".png", "products/inv/abc_large.png"},
+		{"products/inv.d/abc", "large", ".png", "products/inv.d/abc_large.png"},
+		{"products/inv/abc.tar.png", "thumbnail", ".png", "products/inv/abc.tar_thumbnail.png"},
+	}
+
+	for _, tc := range tests {
+		if got := VariantKey(tc.fileKey, tc.name, tc.ext); got != tc.want {
+			t.Errorf("VariantKey(%q, %q, %q) = %q, want %q", tc.fileKey, tc.name, tc.ext, got, tc.want)
+		}
+	}
+}
+
+func TestProcessTransparentPNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 300))
+	img.Set(10, 10, color.NRGBA{R: 255, A: 128})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Original == nil || result.Original.Format != FormatPNG {
+		t.Fatalf("original = %+v, want a re-encoded PNG", result.Original)
+	}
+	if len(result.Variants) != 2*len(Sizes) {
+		t.Fatalf("%d variants, want %d", len(result.Variants), 2*len(Sizes))
+	}
+
+	for _, variant := range result.Variants {
+		if variant.Format != FormatPNG && variant.Format != FormatWebP {
+			t.Errorf("%s variant is %s; transparent images fall back to PNG", variant.Name, variant.Format)
+		}
+		// Never upscaled, always fitted inside the size
+		if variant.Width > 400 || variant.Height > 300 || variant.Width*3 != variant.Height*4 {
+			t.Errorf("%s %s variant is %dx%d", variant.Name, variant.Format, variant.Width, variant.Height)
+		}
+	}
+	if thumb := result.Variants[0]; thumb.Name != "thumbnail" || thumb.Width != 200 || thumb.Height != 150 {
+		t.Errorf("first variant = %s %dx%d, want a 200x150 thumbnail", thumb.Name, thumb.Width, thumb.Height)
+	}
+}
+
+func TestProcessRejectsNonImages(t *testing.T) {
+	if _, err := Process([]byte("not an image")); err == nil {
+		t.Error("expected an error for data that is not an image")
+	}
+}
@@ -65,6 +65,15 @@ func Error(c echo.Context, msg string, err error, fields ...zap.Field) error {
 	return appErr
 }
 
+// LogError logs an error that happened outside of a request, such as in a background job
+func LogError(msg string, err error, fields ...zap.Field) {
+	if globalLogger != nil {
+		globalLogger.mutex.RLock()
+		defer globalLogger.mutex.RUnlock()
+		globalLogger.zapLogger.Error(msg, append(fields, Field("error", err.Error()))...)
+	}
+}
+
 // Fatal logs a fatal message and exits the application
 func Fatal(msg string, fields ...zap.Field) {
 	if globalLogger != nil {
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/venntry/pkg/logger"
+)
+
+// LogMailer is a development sink: messages are logged and, when dir is set, written there
+// as .eml files instead of being delivered
+type LogMailer struct {
+	dir  string
+	from string
+}
+
+func NewLogMailer(dir, from string) *LogMailer {
+	return &LogMailer{dir: dir, from: from}
+}
+
+func (m *LogMailer) Send(msg *Message) error {
+	logger.Info("Email captured by log mailer",
+		logger.Field("to", msg.To),
+		logger.Field("subject", msg.Subject),
+		logger.Field("body", msg.Body),
+	)
+
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), format(m.from, msg), 0o640)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"fmt"
+	"net/mail"
+	"net/smtp"
+)
+
+// SMTPMailer sends mail through an SMTP relay, upgrading to STARTTLS when offered
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: fmt.Sprintf("%s:%d", host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *SMTPMailer) Send(msg *Message) error {
+	// The envelope sender must be a bare address, while the header may carry a display name
+	sender := m.from
+	if addr, err := mail.ParseAddress(m.from); err == nil {
+		sender = addr.Address
+	}
+
+	return smtp.SendMail(m.addr, m.auth, sender, []string{msg.To}, format(m.from, msg))
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LocalStorage keeps objects in a directory, for development and CI. Files are served by
+// Handler and signed uploads received by UploadHandler, both mounted at the path of the
+// public URL.
+type LocalStorage struct {
+	root       string
+	publicURL  string
+	signingKey []byte
+}
+
+func NewLocalStorage(root, publicURL string, signingKey []byte) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{root: root, publicURL: publicURL, signingKey: signingKey}, nil
+}
+
+func (s *LocalStorage) Upload(ctx context.Context, file io.Reader, prefix, filename string) (*UploadResult, error) {
+	fileKey := NewFileKey(prefix, filename)
+	if _, err := s.write(fileKey, file); err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		FileKey: fileKey,
+		URL:     s.URL(fileKey),
+	}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, fileKey string, file io.Reader) error {
+	_, err := s.write(fileKey, file)
+	return err
+}
+
+func (s *LocalStorage) Open(ctx context.Context, fileKey string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(fileKey))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, fileKey string) error {
+	if err := os.Remove(s.path(fileKey)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL returns the public URL with an expiry and signature, which Handler checks
+func (s *LocalStorage) SignedURL(ctx context.Context, fileKey string, ttl time.Duration) (string, error) {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(fileKey, expires))
+
+	return s.URL(fileKey) + "?" + query.Encode(), nil
+}
+
+func (s *LocalStorage) SignedUploadURL(ctx context.Context, fileKey, contentType string, size int64, ttl time.Duration) (*SignedUpload, error) {
+	expiresAt := time.Now().Add(ttl)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	sizeParam := strconv.FormatInt(size, 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("size", sizeParam)
+	query.Set("signature", s.sign(http.MethodPut, fileKey, contentType, sizeParam, expires))
+
+	return &SignedUpload{
+		URL:       s.URL(fileKey) + "?" + query.Encode(),
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *LocalStorage) Head(ctx context.Context, fileKey string) (*ObjectInfo, error) {
+	stat, err := os.Stat(s.path(fileKey))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          fileKey,
+		Size:         stat.Size(),
+		ContentType:  contentTypeOf(fileKey),
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	err := filepath.WalkDir(s.root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         stat.Size(),
+			ContentType:  contentTypeOf(key),
+			LastModified: stat.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+func (s *LocalStorage) URL(fileKey string) string {
+	return joinURL(s.publicURL, fileKey)
+}
+
+// Handler serves stored files for a wildcard route such as "/files/*". Objects are public,
+// as with the bucket drivers, but a signed URL is rejected once it has expired.
+func (s *LocalStorage) Handler() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := strings.TrimPrefix(path.Clean("/"+ctx.Param("*")), "/")
+		if key == "" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+
+		if signature := ctx.QueryParam("signature"); signature != "" {
+			expires := ctx.QueryParam("expires")
+			unix, err := strconv.ParseInt(expires, 10, 64)
+			if err != nil || time.Now().Unix() > unix || !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+				return echo.NewHTTPError(http.StatusForbidden, "invalid or expired signature")
+			}
+		}
+
+		target := s.path(key)
+		if stat, err := os.Stat(target); err != nil || stat.IsDir() {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+
+		return ctx.File(target)
+	}
+}
+
+// UploadHandler receives uploads signed by SignedUploadURL, on the same wildcard route as
+// Handler
+func (s *LocalStorage) UploadHandler() echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := strings.TrimPrefix(path.Clean("/"+ctx.Param("*")), "/")
+		request := ctx.Request()
+
+		expires := ctx.QueryParam("expires")
+		sizeParam := ctx.QueryParam("size")
+		contentType := request.Header.Get(echo.HeaderContentType)
+		expected := s.sign(http.MethodPut, key, contentType, sizeParam, expires)
+
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || time.Now().Unix() > unix || !hmac.Equal([]byte(ctx.QueryParam("signature")), []byte(expected)) {
+			return echo.NewHTTPError(http.StatusForbidden, "invalid or expired signature")
+		}
+
+		size, _ := strconv.ParseInt(sizeParam, 10, 64)
+		if request.ContentLength != size {
+			return echo.NewHTTPError(http.StatusBadRequest, "body does not match the signed size")
+		}
+
+		written, err := s.write(key, io.LimitReader(request.Body, size+1))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to store file")
+		}
+		if written != size {
+			s.Delete(ctx.Request().Context(), key)
+			return echo.NewHTTPError(http.StatusBadRequest, "body does not match the signed size")
+		}
+
+		return ctx.NoContent(http.StatusOK)
+	}
+}
+
+// write stores the object, going through a temporary file so readers never see a partial one
+func (s *LocalStorage) write(fileKey string, file io.Reader) (int64, error) {
+	target := s.path(fileKey)
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, file)
+	if err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return 0, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return written, nil
+}
+
+// path maps a key to a file inside root; cleaning it as an absolute path keeps ".." from
+// escaping the directory
+func (s *LocalStorage) path(fileKey string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path.Clean("/"+fileKey)))
+}
+
+// sign authenticates the given request fields, joined by newlines
+func (s *LocalStorage) sign(fields ...string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
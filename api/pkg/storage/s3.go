@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+type S3Config struct {
+	Endpoint        string // empty for AWS itself, e.g. "http://localhost:9000" for MinIO
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicURL       string // base URL objects are served from
+	UsePathStyle    bool   // required by MinIO and most self-hosted endpoints
+	PublicRead      bool   // upload with the public-read ACL
+}
+
+type R2Config struct {
+	AccountID       string
+	AccessKeyID     string
+	AccessKeySecret string
+	BucketName      string
+	PublicURL       string // e.g., "https://pub-<hash>.r2.dev" or your custom domain
+}
+
+// S3Storage stores objects in an S3-compatible bucket
+type S3Storage struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	config  S3Config
+}
+
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 storage: bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	client := s3.New(s3.Options{
+		Region:       cfg.Region,
+		Credentials:  aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")),
+		UsePathStyle: cfg.UsePathStyle,
+	}, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	})
+
+	return &S3Storage{
+		client:  client,
+		presign: s3.NewPresignClient(client),
+		config:  cfg,
+	}, nil
+}
+
+// NewR2Storage connects to a Cloudflare R2 bucket through its S3-compatible API
+func NewR2Storage(cfg R2Config) (*S3Storage, error) {
+	return NewS3Storage(S3Config{
+		Endpoint:        fmt.Sprintf("https://%s.r2.cloudflarestorage.com", cfg.AccountID),
+		Region:          "auto",
+		Bucket:          cfg.BucketName,
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.AccessKeySecret,
+		PublicURL:       cfg.PublicURL,
+		PublicRead:      true,
+	})
+}
+
+func (s *S3Storage) Upload(ctx context.Context, file io.Reader, prefix, filename string) (*UploadResult, error) {
+	fileKey := NewFileKey(prefix, filename)
+	if err := s.Put(ctx, fileKey, file); err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		FileKey: fileKey,
+		URL:     s.URL(fileKey),
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, fileKey string, file io.Reader) error {
+	// The body must be seekable to be signed, so read it into memory
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, file); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.config.Bucket),
+		Key:         aws.String(fileKey),
+		Body:        bytes.NewReader(buf.Bytes()),
+		ContentType: aws.String(contentTypeOf(fileKey)),
+	}
+	if s.config.PublicRead {
+		input.ACL = types.ObjectCannedACLPublicRead
+	}
+
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+
+	return nil
+}
+
+func (s *S3Storage) Open(ctx context.Context, fileKey string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(fileKey),
+	})
+	if isNotFound(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return output.Body, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, fileKey string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(fileKey),
+	})
+	return err
+}
+
+func (s *S3Storage) SignedURL(ctx context.Context, fileKey string, ttl time.Duration) (string, error) {
+	request, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(fileKey),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+func (s *S3Storage) SignedUploadURL(ctx context.Context, fileKey, contentType string, size int64, ttl time.Duration) (*SignedUpload, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s.config.Bucket),
+		Key:           aws.String(fileKey),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}
+	headers := map[string]string{"Content-Type": contentType}
+	if s.config.PublicRead {
+		input.ACL = types.ObjectCannedACLPublicRead
+		headers["x-amz-acl"] = string(types.ObjectCannedACLPublicRead)
+	}
+
+	request, err := s.presign.PresignPutObject(ctx, input, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign upload: %w", err)
+	}
+
+	return &SignedUpload{
+		URL:       request.URL,
+		Method:    request.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func (s *S3Storage) Head(ctx context.Context, fileKey string) (*ObjectInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	info := &ObjectInfo{
+		Key:         fileKey,
+		Size:        aws.ToInt64(output.ContentLength),
+		ContentType: aws.ToString(output.ContentType),
+	}
+	if output.LastModified != nil {
+		info.LastModified = *output.LastModified
+	}
+
+	return info, nil
+}
+
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.config.Bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			info := ObjectInfo{
+				Key:         aws.ToString(object.Key),
+				Size:        aws.ToInt64(object.Size),
+				ContentType: contentTypeOf(aws.ToString(object.Key)),
+			}
+			if object.LastModified != nil {
+				info.LastModified = *object.LastModified
+			}
+			objects = append(objects, info)
+		}
+	}
+
+	return objects, nil
+}
+
+func (s *S3Storage) URL(fileKey string) string {
+	return joinURL(s.config.PublicURL, fileKey)
+}
+
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Drivers selectable in config
+const (
+	DriverR2    = "r2"
+	DriverS3    = "s3"
+	DriverLocal = "local"
+)
+
+// ErrNotFound is returned by Head for keys that do not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+type UploadResult struct {
+	FileKey string
+	URL     string
+}
+
+// SignedUpload lets a client PUT one object straight to the store. Headers must be sent
+// exactly as given, and the body must be exactly the declared size.
+type SignedUpload struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
+
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// IStorage is an object store for uploaded files. Objects are publicly readable at URL;
+// SignedURL grants time-limited access independent of that. Implementations must be safe for
+// concurrent use.
+type IStorage interface {
+	// Upload stores file under a unique key inside prefix, e.g. "avatars"
+	Upload(ctx context.Context, file io.Reader, prefix, filename string) (*UploadResult, error)
+	// Put writes file to fileKey, replacing any object already stored there
+	Put(ctx context.Context, fileKey string, file io.Reader) error
+	// Open reads an object; the caller closes it
+	Open(ctx context.Context, fileKey string) (io.ReadCloser, error)
+	Delete(ctx context.Context, fileKey string) error
+	SignedURL(ctx context.Context, fileKey string, ttl time.Duration) (string, error)
+	// SignedUploadURL authorizes a direct upload of size bytes of contentType to fileKey
+	SignedUploadURL(ctx context.Context, fileKey, contentType string, size int64, ttl time.Duration) (*SignedUpload, error)
+	Head(ctx context.Context, fileKey string) (*ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	URL(fileKey string) string
+}
+
+// NewFileKey builds a unique key inside prefix that keeps the extension of filename
+func NewFileKey(prefix, filename string) string {
+	return fmt.Sprintf("%s/%s%s", strings.Trim(prefix, "/"), uuid.New().String(), strings.ToLower(filepath.Ext(filename)))
+}
+
+func contentTypeOf(key string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
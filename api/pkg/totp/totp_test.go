@@ -0,0 +1,101 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// Shared secret of the RFC 6238 appendix B vectors for HMAC-SHA1
+const rfcSecret = "12345678901234567890"
+
+// The appendix lists 8-digit values; the 6-digit code is their last six digits
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestGenerateRFC6238Vectors(t *testing.T) {
+	for _, tc := range rfcVectors {
+		if got := generate([]byte(rfcSecret), tc.unix/Period); got != tc.code {
+			t.Errorf("generate at %d = %s, want %s", tc.unix, got, tc.code)
+		}
+	}
+}
+
+func TestValidateRFC6238Vectors(t *testing.T) {
+	secret := encoding.EncodeToString([]byte(rfcSecret))
+
+	for _, tc := range rfcVectors {
+		step, ok := Validate(secret, tc.code, time.Unix(tc.unix, 0))
+		if !ok {
+			t.Errorf("Validate rejected %s at %d", tc.code, tc.unix)
+			continue
+		}
+		if step != tc.unix/Period {
+			t.Errorf("Validate at %d returned step %d, want %d", tc.unix, step, tc.unix/Period)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := encoding.EncodeToString([]byte(rfcSecret))
+	at := time.Unix(1111111111, 0)
+	step := int64(1111111111 / Period)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		at       time.Time
+		wantOK   bool
+		wantStep int64
+	}{
+		{"current period", secret, "050471", at, true, step},
+		{"grouped digits", secret, "050 471", at, true, step},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "050471", at, true, step},
+		{"one period late", secret, "050471", at.Add(Period * time.Second), true, step},
+		{"one period early", secret, "050471", at.Add(-Period * time.Second), true, step},
+		{"two periods late", secret, "050471", at.Add(2 * Period * time.Second), false, 0},
+		{"two periods early", secret, "050471", at.Add(-2 * Period * time.Second), false, 0},
+		{"wrong code", secret, "050472", at, false, 0},
+		{"too short", secret, "50471", at, false, 0},
+		{"eight digits", secret, "14050471", at, false, 0},
+		{"invalid secret", "not base32!", "050471", at, false, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := Validate(tc.secret, tc.code, tc.at)
+			if ok != tc.wantOK || step != tc.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, tc.wantStep, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("secret holds %d bytes, want 20", len(key))
+	}
+
+	now := time.Now()
+	if _, ok := Validate(secret, generate(key, now.Unix()/Period), now); !ok {
+		t.Error("a freshly generated secret does not validate its own code")
+	}
+}